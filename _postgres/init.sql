@@ -3,4 +3,51 @@ CREATE TABLE tasks (
     header TEXT NOT NULL,
     body TEXT NOT NULL,
     isdone BOOLEAN DEFAULT FALSE
-);
\ No newline at end of file
+);
+
+-- Full-text search over header and body. tasks_ru_en stems Cyrillic words
+-- with the Russian dictionary and Latin words with the English one.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'tasks_ru_en') THEN
+        CREATE TEXT SEARCH CONFIGURATION tasks_ru_en (COPY = russian);
+        ALTER TEXT SEARCH CONFIGURATION tasks_ru_en
+            ALTER MAPPING FOR word, hword, hword_part WITH russian_stem;
+        ALTER TEXT SEARCH CONFIGURATION tasks_ru_en
+            ALTER MAPPING FOR asciiword, asciihword, hword_asciipart WITH english_stem;
+    END IF;
+END
+$$;
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('tasks_ru_en', header), 'A') ||
+        setweight(to_tsvector('tasks_ru_en', body), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS tasks_search_vector_idx ON tasks USING GIN (search_vector);
+
+-- Delete only sets deleted_at, rows are removed by the purge job after the retention period.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS tasks_deleted_at_idx ON tasks (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Every change of a task is recorded in the same transaction. There is no foreign key
+-- so the history outlives the purge of the task.
+CREATE TABLE IF NOT EXISTS task_events (
+    id BIGSERIAL PRIMARY KEY,
+    task_id INTEGER NOT NULL,
+    actor TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS task_events_task_id_idx ON task_events (task_id, id);
+
+-- Incremented on every write, used for optimistic concurrency (ETag / If-Match in api-service).
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+-- Free-form labels of a task, read and written as a native array by both repositories.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
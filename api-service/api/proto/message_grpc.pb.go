@@ -1,235 +0,0 @@
-// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
-// versions:
-// - protoc-gen-go-grpc v1.5.1
-// - protoc             v3.21.12
-// source: message.proto
-
-package messagepb
-
-import (
-	context "context"
-	grpc "google.golang.org/grpc"
-	codes "google.golang.org/grpc/codes"
-	status "google.golang.org/grpc/status"
-)
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-// Requires gRPC-Go v1.64.0 or later.
-const _ = grpc.SupportPackageIsVersion9
-
-const (
-	TaskService_Create_FullMethodName = "/messagepb.TaskService/Create"
-	TaskService_List_FullMethodName   = "/messagepb.TaskService/List"
-	TaskService_Delete_FullMethodName = "/messagepb.TaskService/Delete"
-	TaskService_Done_FullMethodName   = "/messagepb.TaskService/Done"
-)
-
-// TaskServiceClient is the client API for TaskService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-type TaskServiceClient interface {
-	Create(ctx context.Context, in *CreateTask, opts ...grpc.CallOption) (*Nothing, error)
-	List(ctx context.Context, in *TaskID, opts ...grpc.CallOption) (*TaskList, error)
-	Delete(ctx context.Context, in *TaskID, opts ...grpc.CallOption) (*Nothing, error)
-	Done(ctx context.Context, in *TaskID, opts ...grpc.CallOption) (*Nothing, error)
-}
-
-type taskServiceClient struct {
-	cc grpc.ClientConnInterface
-}
-
-func NewTaskServiceClient(cc grpc.ClientConnInterface) TaskServiceClient {
-	return &taskServiceClient{cc}
-}
-
-func (c *taskServiceClient) Create(ctx context.Context, in *CreateTask, opts ...grpc.CallOption) (*Nothing, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Nothing)
-	err := c.cc.Invoke(ctx, TaskService_Create_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *taskServiceClient) List(ctx context.Context, in *TaskID, opts ...grpc.CallOption) (*TaskList, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(TaskList)
-	err := c.cc.Invoke(ctx, TaskService_List_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *taskServiceClient) Delete(ctx context.Context, in *TaskID, opts ...grpc.CallOption) (*Nothing, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Nothing)
-	err := c.cc.Invoke(ctx, TaskService_Delete_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *taskServiceClient) Done(ctx context.Context, in *TaskID, opts ...grpc.CallOption) (*Nothing, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Nothing)
-	err := c.cc.Invoke(ctx, TaskService_Done_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-// TaskServiceServer is the server API for TaskService service.
-// All implementations must embed UnimplementedTaskServiceServer
-// for forward compatibility.
-type TaskServiceServer interface {
-	Create(context.Context, *CreateTask) (*Nothing, error)
-	List(context.Context, *TaskID) (*TaskList, error)
-	Delete(context.Context, *TaskID) (*Nothing, error)
-	Done(context.Context, *TaskID) (*Nothing, error)
-	mustEmbedUnimplementedTaskServiceServer()
-}
-
-// UnimplementedTaskServiceServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedTaskServiceServer struct{}
-
-func (UnimplementedTaskServiceServer) Create(context.Context, *CreateTask) (*Nothing, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
-}
-func (UnimplementedTaskServiceServer) List(context.Context, *TaskID) (*TaskList, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
-}
-func (UnimplementedTaskServiceServer) Delete(context.Context, *TaskID) (*Nothing, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
-}
-func (UnimplementedTaskServiceServer) Done(context.Context, *TaskID) (*Nothing, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Done not implemented")
-}
-func (UnimplementedTaskServiceServer) mustEmbedUnimplementedTaskServiceServer() {}
-func (UnimplementedTaskServiceServer) testEmbeddedByValue()                     {}
-
-// UnsafeTaskServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to TaskServiceServer will
-// result in compilation errors.
-type UnsafeTaskServiceServer interface {
-	mustEmbedUnimplementedTaskServiceServer()
-}
-
-func RegisterTaskServiceServer(s grpc.ServiceRegistrar, srv TaskServiceServer) {
-	// If the following call pancis, it indicates UnimplementedTaskServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
-	}
-	s.RegisterService(&TaskService_ServiceDesc, srv)
-}
-
-func _TaskService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateTask)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(TaskServiceServer).Create(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: TaskService_Create_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaskServiceServer).Create(ctx, req.(*CreateTask))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-func _TaskService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TaskID)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(TaskServiceServer).List(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: TaskService_List_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaskServiceServer).List(ctx, req.(*TaskID))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-func _TaskService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TaskID)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(TaskServiceServer).Delete(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: TaskService_Delete_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaskServiceServer).Delete(ctx, req.(*TaskID))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-func _TaskService_Done_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TaskID)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(TaskServiceServer).Done(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: TaskService_Done_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaskServiceServer).Done(ctx, req.(*TaskID))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-// TaskService_ServiceDesc is the grpc.ServiceDesc for TaskService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var TaskService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "messagepb.TaskService",
-	HandlerType: (*TaskServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Create",
-			Handler:    _TaskService_Create_Handler,
-		},
-		{
-			MethodName: "List",
-			Handler:    _TaskService_List_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _TaskService_Delete_Handler,
-		},
-		{
-			MethodName: "Done",
-			Handler:    _TaskService_Done_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "message.proto",
-}
@@ -1,17 +1,30 @@
 package main
 
 import (
-	pb "api-service/api/proto"
+	"api-service/internal/config"
 	"api-service/internal/cruds"
+	"api-service/internal/deadline"
+	"api-service/internal/gateway"
+	"api-service/internal/middleware"
 	"api-service/internal/pkg/logger"
+	"api-service/internal/ratelimit"
+	"api-service/internal/routes"
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"slices"
+	pb "task-api/taskpb/v1"
 
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
 )
 
 func main() {
-	grpcConn, err := grpc.Dial("localhost:8081", grpc.WithInsecure(), grpc.WithBlock())
+	cfg := config.Load()
+
+	grpcConn, err := grpc.Dial(cfg.GRPCAddr, grpc.WithInsecure(), grpc.WithBlock(),
+		grpc.WithUnaryInterceptor(deadline.UnaryClientInterceptor(cfg.RPCTimeout, cfg.RPCTimeouts)))
 	if err != nil {
 		log.Fatalf("failed to connect: %s", err)
 	}
@@ -23,17 +36,65 @@ func main() {
 
 	taskManager := pb.NewTaskServiceClient(grpcConn)
 
-	u := cruds.NewCRUDOperations(taskManager, logger)
+	u := cruds.NewCRUDOperations(taskManager, logger, cfg.MaxBodyBytes)
+
+	gw, err := gateway.NewHandler(context.Background(), grpcConn, logger, cfg.LegacyJSON)
+	if err != nil {
+		log.Fatalf("failed to register gateway: %v", err)
+	}
+
+	// openapi_test.go checks that openapi.json documents the routes of this mux
+	mux := routes.New(middleware.JSONBody(cfg.MaxBodyBytes)(gw), u)
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+	})
+	defer rdb.Close()
 
-	mux := http.NewServeMux()
+	if cfg.CORSAllowCredentials && slices.Contains(cfg.CORSAllowedOrigins, "*") {
+		logger.Logger().Warn().Msg("API_CORS_ALLOW_CREDENTIALS is ignored, API_CORS_ALLOWED_ORIGINS allows any origin")
+	}
 
-	mux.HandleFunc("/create", u.HandleCreate)
-	mux.HandleFunc("/list", u.HandleList)
-	mux.HandleFunc("/delete", u.HandleDelete)
-	mux.HandleFunc("/done", u.HandleDone)
+	middlewares := []func(http.Handler) http.Handler{
+		middleware.Logging(logger),
+		middleware.SecurityHeaders(cfg.HSTSMaxAge, cfg.ContentSecurityPolicy),
+		middleware.CORS(middleware.CORSOptions{
+			AllowedOrigins:   cfg.CORSAllowedOrigins,
+			AllowedMethods:   cfg.CORSAllowedMethods,
+			AllowedHeaders:   cfg.CORSAllowedHeaders,
+			ExposedHeaders:   cfg.CORSExposedHeaders,
+			AllowCredentials: cfg.CORSAllowCredentials,
+			MaxAge:           cfg.CORSMaxAge,
+		}),
+	}
+	if cfg.RateLimitEnabled {
+		middlewares = append(middlewares, middleware.RateLimit(
+			ratelimit.NewLimiter(rdb, "ratelimit:"),
+			middleware.RateLimitOptions{Default: cfg.RateLimitDefault, Routes: cfg.RateLimitRoutes, TrustedProxies: cfg.TrustedProxies},
+			logger,
+		))
+	}
+	handler := middleware.Chain(mux, middlewares...)
 
-	listedAddr := ":8080"
-	log.Printf("starting listining server at %s", listedAddr)
-	http.ListenAndServe(listedAddr, mux)
+	server := &http.Server{
+		Addr:              cfg.HTTPAddr,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
 
+	if cfg.TLSEnabled() {
+		log.Printf("starting listining TLS server at %s", cfg.HTTPAddr)
+		err = server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+	} else {
+		log.Printf("starting listining server at %s", cfg.HTTPAddr)
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("failed to serve: %v", err)
+	}
 }
@@ -0,0 +1,37 @@
+package codec
+
+import (
+	"mime"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	MIMEJSON = "application/json"
+	// MIMELegacyJSON selects the pre-protojson shape, e.g. "Accept: application/vnd.checklist.legacy+json"
+	MIMELegacyJSON = "application/vnd.checklist.legacy+json"
+)
+
+var (
+	// JSON follows the protobuf JSON mapping: camelCase json_name fields, zero values included
+	JSON = protojson.MarshalOptions{EmitUnpopulated: true}
+
+	// LegacyJSON keeps the field names of encoding/json for clients that still depend on them: the names
+	// declared in task.proto ("Header", "ID", "IsDone") with zero values omitted. Values still follow the
+	// protobuf JSON mapping, unlike encoding/json int64 fields such as Version are strings and
+	// Timestamps are RFC 3339 strings instead of objects
+	LegacyJSON = protojson.MarshalOptions{UseProtoNames: true}
+
+	// Unmarshal accepts both shapes, unknown fields are rejected
+	Unmarshal = protojson.UnmarshalOptions{}
+)
+
+// IsJSON reports whether contentType is application/json or a +json media type
+func IsJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == MIMEJSON || strings.HasSuffix(mediaType, "+json")
+}
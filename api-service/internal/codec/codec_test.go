@@ -0,0 +1,47 @@
+package codec
+
+import (
+	"encoding/json"
+	"reflect"
+	pb "task-api/taskpb/v1"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TestLegacyJSONShape pins what clients of the legacy shape get: proto field names, zero values omitted,
+// int64 as strings and RFC 3339 timestamps
+func TestLegacyJSONShape(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		msg  proto.Message
+		want map[string]any
+	}{
+		{
+			name: "task",
+			msg:  &pb.Task{ID: "1", Header: "h", Version: 3, Tags: []string{"a"}},
+			want: map[string]any{"ID": "1", "Header": "h", "Version": "3", "Tags": []any{"a"}},
+		},
+		{
+			name: "trashed task",
+			msg:  &pb.TrashedTask{Task: &pb.Task{ID: "1"}, DeletedAt: timestamppb.New(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))},
+			want: map[string]any{"task": map[string]any{"ID": "1"}, "DeletedAt": "2024-05-01T12:00:00Z"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := LegacyJSON.Marshal(tc.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("LegacyJSON = %s, want %v", data, tc.want)
+			}
+		})
+	}
+}
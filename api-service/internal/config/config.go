@@ -0,0 +1,247 @@
+package config
+
+import (
+	"api-service/internal/ratelimit"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	HTTPAddr string
+	GRPCAddr string
+
+	TLSCertFile string
+	TLSKeyFile  string
+
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	MaxBodyBytes      int64
+
+	// LegacyJSON makes the old encoding/json shape the default of /tasks during the transition to protojson
+	LegacyJSON bool
+
+	HSTSMaxAge            time.Duration
+	ContentSecurityPolicy string
+
+	CORSAllowedOrigins []string
+	CORSAllowedMethods []string
+	CORSAllowedHeaders []string
+	CORSExposedHeaders []string
+	// CORSAllowCredentials is ignored when CORSAllowedOrigins contains "*"
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
+
+	// every RPC to db-service gets RPCTimeout unless RPCTimeouts has one for its method, e.g. "List"
+	RPCTimeout  time.Duration
+	RPCTimeouts map[string]time.Duration
+
+	RedisAddr     string
+	RedisPassword string
+
+	RateLimitEnabled bool
+	RateLimitDefault ratelimit.Limit
+	RateLimitRoutes  map[string]ratelimit.Limit
+	// load balancers in front of api-service, their X-Forwarded-For picks the rate limit bucket of a client
+	TrustedProxies []netip.Prefix
+}
+
+func Load() *Config {
+	return &Config{
+		HTTPAddr: getEnv("API_HTTP_ADDR", ":8080"),
+		GRPCAddr: getEnv("API_GRPC_ADDR", "localhost:8081"),
+
+		TLSCertFile: getEnv("API_TLS_CERT_FILE", ""),
+		TLSKeyFile:  getEnv("API_TLS_KEY_FILE", ""),
+
+		ReadTimeout:       getDuration("API_READ_TIMEOUT", 10*time.Second),
+		ReadHeaderTimeout: getDuration("API_READ_HEADER_TIMEOUT", 5*time.Second),
+		WriteTimeout:      getDuration("API_WRITE_TIMEOUT", 15*time.Second),
+		IdleTimeout:       getDuration("API_IDLE_TIMEOUT", 60*time.Second),
+		MaxHeaderBytes:    getInt("API_MAX_HEADER_BYTES", 1<<20),
+		MaxBodyBytes:      int64(getInt("API_MAX_BODY_BYTES", 1<<20)),
+
+		LegacyJSON: getBool("API_LEGACY_JSON", false),
+
+		HSTSMaxAge:            getDuration("API_HSTS_MAX_AGE", 365*24*time.Hour),
+		ContentSecurityPolicy: getEnv("API_CSP", "default-src 'none'; frame-ancestors 'none'"),
+
+		CORSAllowedOrigins:   getList("API_CORS_ALLOWED_ORIGINS", nil),
+		CORSAllowedMethods:   getList("API_CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+		CORSAllowedHeaders:   getList("API_CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "If-Match", "Idempotency-Key", "X-User-ID", "X-API-Key"}),
+		CORSExposedHeaders:   getList("API_CORS_EXPOSED_HEADERS", []string{"ETag"}),
+		CORSAllowCredentials: getBool("API_CORS_ALLOW_CREDENTIALS", false),
+		CORSMaxAge:           getDuration("API_CORS_MAX_AGE", 10*time.Minute),
+
+		RPCTimeout:  getDuration("API_RPC_TIMEOUT", 5*time.Second),
+		RPCTimeouts: getDurations("API_RPC_TIMEOUTS", map[string]time.Duration{"Search": 10 * time.Second}),
+
+		RedisAddr:     getEnv("API_REDIS_ADDR", "localhost:6379"),
+		RedisPassword: getEnv("API_REDIS_PASSWORD", "redkaPass"),
+
+		RateLimitEnabled: getBool("API_RATE_LIMIT_ENABLED", true),
+		RateLimitDefault: getLimit("API_RATE_LIMIT_DEFAULT", ratelimit.Limit{Rate: 10, Burst: 20}),
+		RateLimitRoutes: getLimits("API_RATE_LIMIT_ROUTES", map[string]ratelimit.Limit{
+			"POST /v1/tasks":             {Rate: 1, Burst: 5},
+			"POST /tasks":                {Rate: 1, Burst: 5},
+			"POST /v2/tasks":             {Rate: 1, Burst: 5},
+			"POST /v1/tasks:batchCreate": {Rate: 1, Burst: 5},
+			"/create":                    {Rate: 1, Burst: 5},
+		}),
+		TrustedProxies: getPrefixes("API_TRUSTED_PROXIES", nil),
+	}
+}
+
+// TLSEnabled reports whether both a certificate and a key are configured
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+func getEnv(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return def
+}
+
+func getBool(key string, def bool) bool {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+// getList parses a comma separated value, empty items are skipped
+func getList(key string, def []string) []string {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getPrefixes parses a comma separated list of CIDRs or single IPs, e.g. "10.0.0.0/8,192.168.1.10".
+// Invalid items are skipped
+func getPrefixes(key string, def []netip.Prefix) []netip.Prefix {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	var prefixes []netip.Prefix
+	for _, item := range strings.Split(v, ",") {
+		item = strings.TrimSpace(item)
+		if p, err := netip.ParsePrefix(item); err == nil {
+			prefixes = append(prefixes, p.Masked())
+		} else if addr, err := netip.ParseAddr(item); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+		}
+	}
+	return prefixes
+}
+
+// getLimit parses "rate:burst", e.g. "10:20" is 10 requests per second with bursts of 20
+func getLimit(key string, def ratelimit.Limit) ratelimit.Limit {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	l, ok := parseLimit(v)
+	if !ok {
+		return def
+	}
+	return l
+}
+
+// getLimits parses "POST /v1/tasks=1:5,/create=1:5,GET /v1/tasks=20:40"
+func getLimits(key string, def map[string]ratelimit.Limit) map[string]ratelimit.Limit {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	limits := make(map[string]ratelimit.Limit)
+	for _, item := range strings.Split(v, ",") {
+		route, spec, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			continue
+		}
+		if l, ok := parseLimit(spec); ok {
+			limits[route] = l
+		}
+	}
+	return limits
+}
+
+func parseLimit(s string) (ratelimit.Limit, bool) {
+	rateStr, burstStr, ok := strings.Cut(s, ":")
+	if !ok {
+		return ratelimit.Limit{}, false
+	}
+	rate, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || rate <= 0 {
+		return ratelimit.Limit{}, false
+	}
+	burst, err := strconv.Atoi(burstStr)
+	if err != nil || burst <= 0 {
+		return ratelimit.Limit{}, false
+	}
+	return ratelimit.Limit{Rate: rate, Burst: burst}, true
+}
+
+func getInt(key string, def int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func getDuration(key string, def time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+// getDurations parses "List=2s,Search=10s"
+func getDurations(key string, def map[string]time.Duration) map[string]time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	durations := make(map[string]time.Duration)
+	for _, item := range strings.Split(v, ",") {
+		name, spec, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			continue
+		}
+		if d, err := time.ParseDuration(spec); err == nil {
+			durations[name] = d
+		}
+	}
+	return durations
+}
@@ -1,28 +1,116 @@
 package cruds
 
 import (
-	pb "api-service/api/proto"
+	"api-service/internal/codec"
 	"api-service/internal/pkg/logger"
+	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	pb "task-api/taskpb/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 type CRUDOperations struct {
-	tsc    pb.TaskServiceClient
-	logger *logger.KafkaLogger
+	tsc          pb.TaskServiceClient
+	logger       *logger.KafkaLogger
+	maxBodyBytes int64
 }
 
-func NewCRUDOperations(tsc pb.TaskServiceClient, logger *logger.KafkaLogger) *CRUDOperations {
+func NewCRUDOperations(tsc pb.TaskServiceClient, logger *logger.KafkaLogger, maxBodyBytes int64) *CRUDOperations {
 	return &CRUDOperations{
-		tsc:    tsc,
-		logger: logger,
+		tsc:          tsc,
+		logger:       logger,
+		maxBodyBytes: maxBodyBytes,
+	}
+}
+
+// decodeError carries the HTTP status and a message that is safe to show to the client
+type decodeError struct {
+	status int
+	msg    string
+}
+
+func (e *decodeError) Error() string {
+	return e.msg
+}
+
+// Helper to decode JSON. The body must be JSON, at most maxBytes long
+// and hold exactly one object without unknown fields.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst proto.Message, maxBytes int64) error {
+	if !codec.IsJSON(r.Header.Get("Content-Type")) {
+		return &decodeError{status: http.StatusUnsupportedMediaType, msg: "Content-Type must be application/json"}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return &decodeError{status: http.StatusRequestEntityTooLarge, msg: fmt.Sprintf("request body must not be larger than %d bytes", maxBytesErr.Limit)}
+		}
+		return &decodeError{status: http.StatusBadRequest, msg: "can't read request body"}
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return &decodeError{status: http.StatusBadRequest, msg: "request body must not be empty"}
+	}
+
+	// protojson rejects unknown fields and anything after the first value
+	if err := codec.Unmarshal.Unmarshal(body, dst); err != nil {
+		return &decodeError{status: http.StatusBadRequest, msg: "invalid request body: " + err.Error()}
+	}
+
+	return nil
+}
+
+// writeDecodeError answers with the status of a decodeError, or 400 for anything else
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var decErr *decodeError
+	if errors.As(err, &decErr) {
+		http.Error(w, decErr.msg, decErr.status)
+		return
 	}
+	http.Error(w, "Bad request", http.StatusBadRequest)
 }
 
-// Helper to decode JSON
-func decodeJSON[T any](r *http.Request, dst *T) error {
-	return json.NewDecoder(r.Body).Decode(dst)
+// rpcContext forwards the headers db-service uses for writes, like the gateway does for the REST routes.
+// Every value is kept, db-service reads If-Match as a list
+func rpcContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, h := range []string{"X-User-ID", "Idempotency-Key", "If-Match"} {
+		if v := r.Header.Values(h); len(v) > 0 {
+			md.Set(strings.ToLower(h), v...)
+		}
+	}
+	return metadata.NewOutgoingContext(r.Context(), md)
+}
+
+// statusClientClosedRequest is the nginx status for a client that went away before the answer
+const statusClientClosedRequest = 499
+
+// writeRPCError answers 400, 404 and 409 for the request errors of db-service, 504 if the RPC ran out of time,
+// 499 if the client canceled it and 500 for anything else
+func writeRPCError(w http.ResponseWriter, err error) {
+	switch status.Code(err) {
+	case codes.DeadlineExceeded:
+		http.Error(w, "db-service did not answer in time", http.StatusGatewayTimeout)
+	case codes.Canceled:
+		http.Error(w, "request canceled", statusClientClosedRequest)
+	case codes.InvalidArgument:
+		http.Error(w, status.Convert(err).Message(), http.StatusBadRequest)
+	case codes.NotFound:
+		http.Error(w, status.Convert(err).Message(), http.StatusNotFound)
+	case codes.Aborted:
+		http.Error(w, status.Convert(err).Message(), http.StatusConflict)
+	default:
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
 }
 
 // POST /create
@@ -35,17 +123,17 @@ func (crud *CRUDOperations) HandleCreate(w http.ResponseWriter, r *http.Request)
 	}
 
 	var task pb.CreateTask
-	if err := decodeJSON(r, &task); err != nil {
+	if err := decodeJSON(w, r, &task, crud.maxBodyBytes); err != nil {
 		crud.logger.Logger().Error().Err(err).Msg("failed to decode CreateTask")
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		writeDecodeError(w, err)
 		return
 	}
 
 	crud.logger.Logger().Info().Msg("RPC call CreateTask")
-	_, err := crud.tsc.Create(r.Context(), &task)
+	_, err := crud.tsc.Create(rpcContext(r), &task)
 	if err != nil {
 		crud.logger.Logger().Error().Err(err).Msg("CreateTask RPC failed")
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		writeRPCError(w, err)
 		return
 	}
 
@@ -62,9 +150,17 @@ func (crud *CRUDOperations) HandleList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	crud.logger.Logger().Info().Msg("RPC call List")
-	tasksList, err := crud.tsc.List(context.Background(), &pb.TaskID{ID: "1"})
+	tasksList, err := crud.tsc.List(rpcContext(r), &pb.TaskID{ID: "1"})
 	if err != nil {
 		crud.logger.Logger().Error().Err(err).Msg("List RPC failed")
+		writeRPCError(w, err)
+		return
+	}
+
+	// the deprecated routes keep the legacy JSON shape their clients were built against
+	data, err := codec.LegacyJSON.Marshal(tasksList)
+	if err != nil {
+		crud.logger.Logger().Error().Err(err).Msg("failed to encode JSON response")
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -72,9 +168,8 @@ func (crud *CRUDOperations) HandleList(w http.ResponseWriter, r *http.Request) {
 	crud.logger.Logger().Info().Msg("send HandleList response")
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(tasksList); err != nil {
-		crud.logger.Logger().Error().Err(err).Msg("failed to encode JSON response")
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	if _, err := w.Write(data); err != nil {
+		crud.logger.Logger().Error().Err(err).Msg("failed to write JSON response")
 		return
 	}
 }
@@ -89,17 +184,17 @@ func (crud *CRUDOperations) HandleDelete(w http.ResponseWriter, r *http.Request)
 	}
 
 	var taskID pb.TaskID
-	if err := decodeJSON(r, &taskID); err != nil {
+	if err := decodeJSON(w, r, &taskID, crud.maxBodyBytes); err != nil {
 		crud.logger.Logger().Error().Err(err).Msg("failed to decode TaskID")
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		writeDecodeError(w, err)
 		return
 	}
 
 	crud.logger.Logger().Info().Msg("RPC call DeleteTask")
-	_, err := crud.tsc.Delete(r.Context(), &taskID)
+	_, err := crud.tsc.Delete(rpcContext(r), &taskID)
 	if err != nil {
 		crud.logger.Logger().Error().Err(err).Msg("DeleteTask RPC failed")
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		writeRPCError(w, err)
 		return
 	}
 
@@ -116,17 +211,17 @@ func (crud *CRUDOperations) HandleDone(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var taskID pb.TaskID
-	if err := decodeJSON(r, &taskID); err != nil {
+	if err := decodeJSON(w, r, &taskID, crud.maxBodyBytes); err != nil {
 		crud.logger.Logger().Error().Err(err).Msg("failed to decode TaskID")
-		http.Error(w, "Bad request", http.StatusBadRequest)
+		writeDecodeError(w, err)
 		return
 	}
 
 	crud.logger.Logger().Info().Msg("RPC call Done")
-	_, err := crud.tsc.Done(r.Context(), &taskID)
+	_, err := crud.tsc.Done(rpcContext(r), &taskID)
 	if err != nil {
 		crud.logger.Logger().Error().Err(err).Msg("Done RPC failed")
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		writeRPCError(w, err)
 		return
 	}
 
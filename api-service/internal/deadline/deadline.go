@@ -0,0 +1,28 @@
+package deadline
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// UnaryClientInterceptor gives every RPC a deadline. methods holds the timeouts of single methods by their short
+// name like "List", the others get def. A caller with an earlier deadline keeps it, zero or negative timeouts
+// leave the call without one
+func UnaryClientInterceptor(def time.Duration, methods map[string]time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		timeout, ok := methods[path.Base(method)]
+		if !ok {
+			timeout = def
+		}
+		if timeout <= 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
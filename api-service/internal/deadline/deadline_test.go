@@ -0,0 +1,65 @@
+package deadline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// remaining calls the interceptor for method and returns how long the invoker had, ok is false without a deadline
+func remaining(t *testing.T, ctx context.Context, method string) (time.Duration, bool) {
+	t.Helper()
+	interceptor := UnaryClientInterceptor(5*time.Second, map[string]time.Duration{"Search": 10 * time.Second, "List": 0})
+
+	var (
+		left time.Duration
+		ok   bool
+	)
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		var deadline time.Time
+		deadline, ok = ctx.Deadline()
+		left = time.Until(deadline)
+		return nil
+	}
+	if err := interceptor(ctx, method, nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	return left, ok
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		want   time.Duration
+	}{
+		{"default", "/taskpb.v1.TaskService/Get", 5 * time.Second},
+		{"per method", "/taskpb.v1.TaskService/Search", 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			left, ok := remaining(t, context.Background(), tt.method)
+			if !ok || left > tt.want || left < tt.want-time.Second {
+				t.Fatalf("deadline in %s (%v), want %s", left, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptorZeroTimeout(t *testing.T) {
+	if left, ok := remaining(t, context.Background(), "/taskpb.v1.TaskService/List"); ok {
+		t.Fatalf("List got a deadline in %s, want none", left)
+	}
+}
+
+func TestUnaryClientInterceptorKeepsEarlierDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	left, ok := remaining(t, ctx, "/taskpb.v1.TaskService/Search")
+	if !ok || left > time.Second {
+		t.Fatalf("deadline in %s (%v), want the caller's second", left, ok)
+	}
+}
@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"api-service/internal/codec"
+	"api-service/internal/pkg/logger"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	pb "task-api/taskpb/v1"
+	pbv2 "task-api/taskpb/v2"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// headers forwarded to db-service as gRPC metadata
+var forwardedHeaders = map[string]string{
+	"x-user-id": "x-user-id",
+	"x-api-key": "x-api-key",
+	"if-match":  "if-match",
+	// replays of writes with the same key return the first response
+	"idempotency-key": "idempotency-key",
+}
+
+// NewHandler returns the REST API generated from the google.api.http annotations of TaskService.
+// v1 requests are proxied to db-service over conn, v2 requests are served by v2Server. Clients get the legacy JSON shape when they
+// send "Accept: application/vnd.checklist.legacy+json", or by default when legacyJSON is set.
+func NewHandler(ctx context.Context, conn *grpc.ClientConn, logger *logger.KafkaLogger, legacyJSON bool) (http.Handler, error) {
+	defaultJSON := codec.JSON
+	if legacyJSON {
+		defaultJSON = codec.LegacyJSON
+	}
+
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   defaultJSON,
+			UnmarshalOptions: codec.Unmarshal,
+		}),
+		runtime.WithMarshalerOption(codec.MIMELegacyJSON, &runtime.JSONPb{
+			MarshalOptions:   codec.LegacyJSON,
+			UnmarshalOptions: codec.Unmarshal,
+		}),
+		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			if md, ok := forwardedHeaders[strings.ToLower(key)]; ok {
+				return md, true
+			}
+			return runtime.DefaultHeaderMatcher(key)
+		}),
+		runtime.WithForwardResponseOption(func(ctx context.Context, w http.ResponseWriter, m proto.Message) error {
+			switch t := m.(type) {
+			case *pb.Task:
+				w.Header().Set("ETag", etag(t.Version))
+			case *pbv2.Task:
+				w.Header().Set("ETag", etag(t.Version))
+			}
+
+			method, _ := runtime.RPCMethod(ctx)
+			if method == pb.TaskService_Create_FullMethodName || method == pbv2.TaskService_CreateTask_FullMethodName {
+				w.WriteHeader(http.StatusCreated)
+			}
+			return nil
+		}),
+		runtime.WithErrorHandler(func(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+			logger.Logger().Error().Err(err).Str("method", r.Method).Str("path", r.URL.Path).Msg("gateway request failed")
+			runtime.DefaultHTTPErrorHandler(ctx, mux, m, w, r, err)
+		}),
+	)
+
+	if err := pb.RegisterTaskServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := pbv2.RegisterTaskServiceHandlerServer(ctx, mux, &v2Server{tsc: pb.NewTaskServiceClient(conn)}); err != nil {
+		return nil, err
+	}
+	return mux, nil
+}
+
+// etag is the task version in quotes, db-service accepts it back in If-Match
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// Patterns returns the routes of the generated REST API as "METHOD /path"
+func Patterns() []string {
+	var patterns []string
+	for _, svc := range []protoreflect.ServiceDescriptor{
+		pb.File_taskpb_v1_task_proto.Services().ByName("TaskService"),
+		pbv2.File_taskpb_v2_task_proto.Services().ByName("TaskService"),
+	} {
+		methods := svc.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			patterns = append(patterns, httpPatterns(methods.Get(i))...)
+		}
+	}
+	return patterns
+}
+
+// httpPatterns returns the google.api.http rule of m and its additional bindings
+func httpPatterns(m protoreflect.MethodDescriptor) []string {
+	rule, ok := proto.GetExtension(m.Options(), annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return nil
+	}
+
+	var patterns []string
+	for _, r := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+		if p, ok := httpPattern(r); ok {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+func httpPattern(rule *annotations.HttpRule) (string, bool) {
+	switch p := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return http.MethodGet + " " + p.Get, true
+	case *annotations.HttpRule_Post:
+		return http.MethodPost + " " + p.Post, true
+	case *annotations.HttpRule_Put:
+		return http.MethodPut + " " + p.Put, true
+	case *annotations.HttpRule_Patch:
+		return http.MethodPatch + " " + p.Patch, true
+	case *annotations.HttpRule_Delete:
+		return http.MethodDelete + " " + p.Delete, true
+	case *annotations.HttpRule_Custom:
+		return p.Custom.GetKind() + " " + p.Custom.GetPath(), true
+	}
+	return "", false
+}
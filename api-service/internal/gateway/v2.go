@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"context"
+	pb "task-api/taskpb/v1"
+	pbv2 "task-api/taskpb/v2"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// v2Server serves TaskService v2 inside api-service by translating every call
+// to the v1 TaskService of db-service, so db-service only has to implement v1.
+type v2Server struct {
+	pbv2.UnimplementedTaskServiceServer
+	tsc pb.TaskServiceClient
+}
+
+// outgoing forwards the metadata the gateway extracted from HTTP headers to db-service
+func outgoing(ctx context.Context) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		return metadata.NewOutgoingContext(ctx, md)
+	}
+	return ctx
+}
+
+func taskToV2(t *pb.Task) *pbv2.Task {
+	return &pbv2.Task{
+		Id:      t.ID,
+		Header:  t.Header,
+		Body:    t.Body,
+		IsDone:  t.IsDone,
+		Version: t.Version,
+		Tags:    t.Tags,
+	}
+}
+
+func (s *v2Server) CreateTask(ctx context.Context, in *pbv2.CreateTaskRequest) (*emptypb.Empty, error) {
+	if _, err := s.tsc.Create(outgoing(ctx), &pb.CreateTask{Header: in.Header, Body: in.Body, Tags: in.Tags}); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *v2Server) ListTasks(ctx context.Context, in *pbv2.ListTasksRequest) (*pbv2.ListTasksResponse, error) {
+	list, err := s.tsc.List(outgoing(ctx), &pb.TaskID{})
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*pbv2.Task, 0, len(list.Tasks))
+	for _, t := range list.Tasks {
+		tasks = append(tasks, taskToV2(t))
+	}
+	return &pbv2.ListTasksResponse{Tasks: tasks}, nil
+}
+
+func (s *v2Server) GetTask(ctx context.Context, in *pbv2.GetTaskRequest) (*pbv2.Task, error) {
+	t, err := s.tsc.Get(outgoing(ctx), &pb.TaskID{ID: in.Id})
+	if err != nil {
+		return nil, err
+	}
+	return taskToV2(t), nil
+}
+
+func (s *v2Server) UpdateTask(ctx context.Context, in *pbv2.UpdateTaskRequest) (*pbv2.Task, error) {
+	ctx = outgoing(ctx)
+	if _, err := s.tsc.Update(ctx, &pb.UpdateTask{ID: in.Id, Header: in.Header, Body: in.Body, IsDone: in.IsDone}); err != nil {
+		return nil, err
+	}
+	return s.GetTask(ctx, &pbv2.GetTaskRequest{Id: in.Id})
+}
+
+func (s *v2Server) DeleteTask(ctx context.Context, in *pbv2.DeleteTaskRequest) (*emptypb.Empty, error) {
+	if _, err := s.tsc.Delete(outgoing(ctx), &pb.TaskID{ID: in.Id}); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *v2Server) DoneTask(ctx context.Context, in *pbv2.DoneTaskRequest) (*pbv2.Task, error) {
+	ctx = outgoing(ctx)
+	if _, err := s.tsc.Done(ctx, &pb.TaskID{ID: in.Id}); err != nil {
+		return nil, err
+	}
+	return s.GetTask(ctx, &pbv2.GetTaskRequest{Id: in.Id})
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"api-service/internal/codec"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// JSONBody rejects request bodies that are not JSON (415), larger than maxBytes (413) or hold more than one
+// JSON value (400). The body is read up front, so handlers that don't use decodeJSON, like the gRPC gateway,
+// are limited too.
+func JSONBody(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !codec.IsJSON(r.Header.Get("Content-Type")) {
+				http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					http.Error(w, fmt.Sprintf("request body must not be larger than %d bytes", maxBytes), http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(w, "Bad request", http.StatusBadRequest)
+				return
+			}
+
+			// the gateway decodes with json.Decoder, which ignores anything after the first value
+			if len(body) > 0 && !singleJSONValue(body) {
+				http.Error(w, "request body must hold exactly one JSON value", http.StatusBadRequest)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func singleJSONValue(body []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	var v json.RawMessage
+	if err := dec.Decode(&v); err != nil {
+		return false
+	}
+	_, err := dec.Token()
+	return err == io.EOF
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJSONBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		code        int
+	}{
+		{"one object", "application/json", `{"header":"a"}`, http.StatusOK},
+		{"surrounding whitespace", "application/json", " {\"header\":\"a\"}\n", http.StatusOK},
+		{"no body", "", "", http.StatusOK},
+		{"two objects", "application/json", `{"header":"a"} {"header":"b"}`, http.StatusBadRequest},
+		{"trailing garbage", "application/json", `{"header":"a"}x`, http.StatusBadRequest},
+		{"invalid JSON", "application/json", `{"header":`, http.StatusBadRequest},
+		{"not JSON", "text/plain", `{"header":"a"}`, http.StatusUnsupportedMediaType},
+		{"too large", "application/json", `{"header":"` + strings.Repeat("a", 64) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := JSONBody(64)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				got = string(body)
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/v1/tasks", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.code {
+				t.Fatalf("status = %d, want %d", w.Code, tt.code)
+			}
+			if tt.code == http.StatusOK && got != tt.body {
+				t.Fatalf("handler read %q, want the whole body %q", got, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package middleware
+
+import "net/http"
+
+// Chain wraps h with mws, the first middleware is the outermost one
+func Chain(h http.Handler, mws ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import "net/http"
+
+// Deprecated marks responses of a legacy route with a Deprecation header
+// and points clients to the route that replaces it.
+func Deprecated(successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"api-service/internal/pkg/logger"
+	"api-service/internal/ratelimit"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+type RateLimitOptions struct {
+	Default ratelimit.Limit
+	// Routes overrides Default for requests matching the key, an http.ServeMux pattern such as
+	// "POST /v1/tasks" or "/create". The most specific pattern wins like in a ServeMux
+	Routes map[string]ratelimit.Limit
+	// TrustedProxies may set X-Forwarded-For, the client IP is the last address in it that isn't one of them
+	TrustedProxies []netip.Prefix
+}
+
+// RateLimit limits requests per client and route. A client that sends X-API-Key gets the bucket of its key,
+// so clients behind one NAT or load balancer don't share one. api-service doesn't validate keys, only their
+// hash is stored. Other clients are identified by their IP. If Redis fails the request is let through.
+// It panics on invalid or conflicting patterns in opts.Routes
+func RateLimit(limiter *ratelimit.Limiter, opts RateLimitOptions, logger *logger.KafkaLogger) func(http.Handler) http.Handler {
+	routes := http.NewServeMux()
+	for pattern := range opts.Routes {
+		routes.Handle(pattern, http.NotFoundHandler())
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			route, limit := matchRoute(routes, r, opts)
+			key := route + ":" + clientKey(r, opts.TrustedProxies)
+
+			res, err := limiter.Allow(r.Context(), key, limit)
+			if err != nil {
+				logger.Logger().Warn().Err(err).Str("key", key).Msg("rate limiter unavailable, request allowed")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("X-RateLimit-Reset", strconv.Itoa(int(res.ResetAfter.Seconds())))
+
+			if !res.Allowed {
+				logger.Logger().Warn().Str("key", key).Msg("rate limit exceeded")
+				h.Set("Retry-After", strconv.Itoa(int(res.RetryAfter.Seconds())))
+				http.Error(w, "Too many requests", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func matchRoute(routes *http.ServeMux, r *http.Request, opts RateLimitOptions) (string, ratelimit.Limit) {
+	if _, pattern := routes.Handler(r); pattern != "" {
+		return pattern, opts.Routes[pattern]
+	}
+	return "*", opts.Default
+}
+
+func clientKey(r *http.Request, trusted []netip.Prefix) string {
+	if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+		sum := sha256.Sum256([]byte(apiKey))
+		return "key:" + hex.EncodeToString(sum[:16])
+	}
+	return "ip:" + clientIP(r, trusted)
+}
+
+// clientIP returns the remote address, or if it is a trusted proxy the last address of X-Forwarded-For that
+// isn't one. Addresses before it could be set by the client itself
+func clientIP(r *http.Request, trusted []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := host
+	var forwarded []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(v, ",")...)
+	}
+	for i := len(forwarded); isTrusted(ip, trusted) && i > 0; i-- {
+		ip = strings.TrimSpace(forwarded[i-1])
+	}
+	return ip
+}
+
+func isTrusted(ip string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
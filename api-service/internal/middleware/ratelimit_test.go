@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"api-service/internal/pkg/logger"
+	"api-service/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newRateLimited(t *testing.T, opts RateLimitOptions) (http.Handler, *miniredis.Miniredis) {
+	t.Helper()
+	m := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	return RateLimit(ratelimit.NewLimiter(rdb, "ratelimit:"), opts, logger.NewNopLogger())(ok), m
+}
+
+func send(h http.Handler, method, path, remoteAddr string, header http.Header) int {
+	r := httptest.NewRequest(method, path, nil)
+	r.RemoteAddr = remoteAddr
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code
+}
+
+func TestRateLimitRoutes(t *testing.T) {
+	h, _ := newRateLimited(t, RateLimitOptions{
+		Default: ratelimit.Limit{Rate: 1, Burst: 3},
+		Routes:  map[string]ratelimit.Limit{"POST /v1/tasks": {Rate: 1, Burst: 1}},
+	})
+	const client = "192.0.2.1:1234"
+
+	if code := send(h, http.MethodPost, "/v1/tasks", client, nil); code != http.StatusOK {
+		t.Fatalf("first create = %d, want 200", code)
+	}
+	if code := send(h, http.MethodPost, "/v1/tasks", client, nil); code != http.StatusTooManyRequests {
+		t.Fatalf("second create = %d, want 429", code)
+	}
+	// GET /v1/tasks doesn't match the POST pattern, it has the default bucket
+	for i := range 3 {
+		if code := send(h, http.MethodGet, "/v1/tasks", client, nil); code != http.StatusOK {
+			t.Fatalf("list %d = %d, want 200", i, code)
+		}
+	}
+	if code := send(h, http.MethodGet, "/v1/tasks/1", client, nil); code != http.StatusTooManyRequests {
+		t.Fatalf("request over the default burst = %d, want 429", code)
+	}
+}
+
+func TestRateLimitClients(t *testing.T) {
+	h, _ := newRateLimited(t, RateLimitOptions{
+		Default:        ratelimit.Limit{Rate: 1, Burst: 1},
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	})
+	const proxy = "10.0.0.5:1234"
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     http.Header
+		// the request must land in the bucket of an earlier one, which is empty by then
+		shares bool
+	}{
+		{"direct client", "192.0.2.1:1", nil, false},
+		{"same IP, other port", "192.0.2.1:2", nil, true},
+		{"API key from the same IP", "192.0.2.1:3", http.Header{"X-Api-Key": {"k1"}}, false},
+		{"same API key from another IP", "198.51.100.1:1", http.Header{"X-Api-Key": {"k1"}}, true},
+		{"other API key behind the proxy", proxy, http.Header{"X-Api-Key": {"k2"}}, false},
+		{"client behind the proxy", proxy, http.Header{"X-Forwarded-For": {"203.0.113.7"}}, false},
+		{"other client behind the proxy", proxy, http.Header{"X-Forwarded-For": {"203.0.113.8, 10.0.0.9"}}, false},
+		{"spoofed X-Forwarded-For behind the proxy", proxy, http.Header{"X-Forwarded-For": {"198.51.100.99, 203.0.113.7"}}, true},
+		// only trusted proxies may set X-Forwarded-For
+		{"X-Forwarded-For from a client", "192.0.2.1:4", http.Header{"X-Forwarded-For": {"203.0.113.9"}}, true},
+	}
+	for _, tt := range tests {
+		want := http.StatusOK
+		if tt.shares {
+			want = http.StatusTooManyRequests
+		}
+		if code := send(h, http.MethodGet, "/v1/tasks", tt.remoteAddr, tt.header); code != want {
+			t.Errorf("%s = %d, want %d", tt.name, code, want)
+		}
+	}
+}
+
+func TestRateLimitAllowsWhenRedisFails(t *testing.T) {
+	h, m := newRateLimited(t, RateLimitOptions{Default: ratelimit.Limit{Rate: 1, Burst: 1}})
+	m.Close()
+
+	for range 3 {
+		if code := send(h, http.MethodGet, "/v1/tasks", "192.0.2.1:1", nil); code != http.StatusOK {
+			t.Fatalf("request with Redis down = %d, want 200", code)
+		}
+	}
+}
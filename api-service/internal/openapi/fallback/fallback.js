@@ -0,0 +1,39 @@
+// renders the operations of /openapi.json while the Swagger UI files are not vendored
+function el(tag, text, className) {
+  var e = document.createElement(tag);
+  if (text) e.textContent = text;
+  if (className) e.className = className;
+  return e;
+}
+
+function operation(method, path, op) {
+  var details = el("details", null, "op");
+  var summary = el("summary", null, op.deprecated ? "deprecated" : "");
+  summary.appendChild(el("span", method.toUpperCase(), "method"));
+  summary.appendChild(document.createTextNode(path + (op.summary ? " - " + op.summary : "")));
+  details.appendChild(summary);
+  if (op.description) details.appendChild(el("p", op.description));
+  details.appendChild(el("pre", JSON.stringify({
+    parameters: op.parameters,
+    requestBody: op.requestBody,
+    responses: op.responses,
+  }, null, 2)));
+  return details;
+}
+
+fetch("/openapi.json")
+  .then(function (res) { return res.json(); })
+  .then(function (spec) {
+    var docs = document.getElementById("docs");
+    docs.appendChild(el("h1", spec.info.title + " " + spec.info.version));
+    Object.keys(spec.paths).forEach(function (path) {
+      Object.keys(spec.paths[path]).forEach(function (method) {
+        if (method !== "parameters") docs.appendChild(operation(method, path, spec.paths[path][method]));
+      });
+    });
+    docs.appendChild(el("h2", "Schemas"));
+    docs.appendChild(el("pre", JSON.stringify(spec.components.schemas, null, 2)));
+  })
+  .catch(function (err) {
+    document.getElementById("docs").appendChild(el("p", "Failed to load /openapi.json: " + err));
+  });
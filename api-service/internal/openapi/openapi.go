@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+// swagger-ui holds the swagger-ui-dist files of the version in swagger-ui/VERSION, make swagger-ui vendors them.
+// fallback is served instead while they are missing, it lists the operations of openapi.json without Swagger UI
+//
+//go:embed swagger-ui fallback
+var docs embed.FS
+
+// the docs pages only load the embedded files, the API keeps its stricter CSP
+const docsCSP = "default-src 'none'; script-src 'self'; style-src 'self'; img-src 'self' data:; connect-src 'self'; frame-ancestors 'none'"
+
+// Register adds GET /openapi.json and the Swagger UI under /docs/ to mux
+func Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	})
+	mux.Handle("GET /docs", http.RedirectHandler("/docs/", http.StatusMovedPermanently))
+
+	files := http.StripPrefix("/docs/", http.FileServerFS(docsFS()))
+	mux.HandleFunc("GET /docs/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", docsCSP)
+		files.ServeHTTP(w, r)
+	})
+}
+
+// docsFS returns the Swagger UI files, or the fallback page if swagger-ui-bundle.js is not vendored
+func docsFS() fs.FS {
+	dir := "swagger-ui"
+	if _, err := fs.Stat(docs, "swagger-ui/swagger-ui-bundle.js"); err != nil {
+		dir = "fallback"
+	}
+	sub, _ := fs.Sub(docs, dir)
+	return sub
+}
+
+// Operations returns every operation of the document as "METHOD /path"
+func Operations() ([]string, error) {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("can't parse openapi.json: %w", err)
+	}
+
+	var ops []string
+	for path, item := range doc.Paths {
+		for method := range item {
+			switch method {
+			case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+				ops = append(ops, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	sort.Strings(ops)
+	return ops, nil
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// normalize drops path parameter names, so "/tasks/{id}" and "/tasks/{ID}" are the same route
+func normalize(route string) string {
+	return pathParam.ReplaceAllString(route, "{}")
+}
+
+// Verify checks that the document and the router patterns describe the same routes.
+// A pattern without a method, like the legacy "/create", matches any documented method of its path.
+func Verify(patterns []string) error {
+	ops, err := Operations()
+	if err != nil {
+		return err
+	}
+	for i := range ops {
+		ops[i] = normalize(ops[i])
+	}
+
+	documented := make(map[string]bool, len(ops))
+	documentedPaths := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		documented[op] = true
+		_, path, _ := strings.Cut(op, " ")
+		documentedPaths[path] = true
+	}
+
+	registered := make(map[string]bool, len(patterns))
+	registeredPaths := make(map[string]bool, len(patterns))
+	var missing []string
+	for _, p := range patterns {
+		route := normalize(p)
+		if !strings.Contains(route, " ") {
+			registeredPaths[route] = true
+			if !documentedPaths[route] {
+				missing = append(missing, p)
+			}
+			continue
+		}
+		registered[route] = true
+		if !documented[route] {
+			missing = append(missing, p)
+		}
+	}
+
+	var extra []string
+	for _, op := range ops {
+		_, path, _ := strings.Cut(op, " ")
+		if !registered[op] && !registeredPaths[path] {
+			extra = append(extra, op)
+		}
+	}
+
+	if len(missing) > 0 || len(extra) > 0 {
+		return fmt.Errorf("openapi.json is out of sync with the router: undocumented %v, not registered %v", missing, extra)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package openapi_test
+
+import (
+	"api-service/internal/openapi"
+	"api-service/internal/routes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	patterns := routes.Patterns()
+	if err := openapi.Verify(patterns); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyReportsUndocumentedRoutes(t *testing.T) {
+	patterns := append(routes.Patterns(), "GET /v1/undocumented")
+	if err := openapi.Verify(patterns); err == nil {
+		t.Fatal("Verify accepted a route that is not in openapi.json")
+	}
+}
+
+func TestDocs(t *testing.T) {
+	mux := http.NewServeMux()
+	openapi.Register(mux)
+
+	for _, path := range []string{"/docs/", "/openapi.json"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d, want 200", path, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/", nil))
+	csp := w.Header().Get("Content-Security-Policy")
+	if !strings.Contains(csp, "script-src 'self'") || strings.Contains(csp, "http") {
+		t.Fatalf("CSP of /docs/ = %q, want only the embedded scripts", csp)
+	}
+	// every script of the page must be embedded, a CDN would be blocked by the CSP
+	for _, src := range regexp.MustCompile(`src="([^"]+)"`).FindAllStringSubmatch(w.Body.String(), -1) {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/"+src[1], nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /docs/%s = %d, want 200", src[1], rec.Code)
+		}
+	}
+}
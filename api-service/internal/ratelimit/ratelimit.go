@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limit is a token bucket: Rate tokens per second are added up to Burst tokens
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+// The bucket lives in a Redis hash so every api-service replica shares it.
+// Redis TIME is used instead of the caller's clock to keep replicas consistent.
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+if tokens >= cost then
+	tokens = tokens - cost
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+type Limiter struct {
+	rdb    *redis.Client
+	prefix string
+}
+
+func NewLimiter(rdb *redis.Client, prefix string) *Limiter {
+	return &Limiter{
+		rdb:    rdb,
+		prefix: prefix,
+	}
+}
+
+// Allow takes one token from the bucket identified by key
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	res, err := tokenBucket.Run(ctx, l.rdb, []string{l.prefix + key}, limit.Rate, limit.Burst, 1).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed, _ := res[0].(int64)
+	tokensStr, _ := res[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{
+		Allowed:    allowed == 1,
+		Limit:      limit.Burst,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: secondsUntil(float64(limit.Burst)-tokens, limit.Rate),
+	}
+	if !result.Allowed {
+		result.RetryAfter = secondsUntil(1-tokens, limit.Rate)
+	}
+
+	return result, nil
+}
+
+// secondsUntil returns how long it takes to refill missing tokens, rounded up to whole seconds
+func secondsUntil(missing, rate float64) time.Duration {
+	if missing <= 0 || rate <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(missing/rate)) * time.Second
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestLimiter(t *testing.T) (*Limiter, *miniredis.Miniredis) {
+	t.Helper()
+	m := miniredis.RunT(t)
+	m.SetTime(time.Unix(1700000000, 0))
+	rdb := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return NewLimiter(rdb, "ratelimit:"), m
+}
+
+func TestTokenBucket(t *testing.T) {
+	l, m := newTestLimiter(t)
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 3}
+
+	for i := range 3 {
+		res, err := l.Allow(ctx, "a", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Allowed || res.Remaining != 2-i || res.Limit != 3 {
+			t.Fatalf("request %d = %+v, want allowed with %d remaining", i, res, 2-i)
+		}
+	}
+
+	res, err := l.Allow(ctx, "a", limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Allowed || res.RetryAfter != time.Second || res.ResetAfter != 3*time.Second {
+		t.Fatalf("request over the burst = %+v, want denied, retry after 1s, reset after 3s", res)
+	}
+
+	// other keys have their own bucket
+	if res, _ := l.Allow(ctx, "b", limit); !res.Allowed {
+		t.Fatal("bucket of b was empty")
+	}
+
+	// a token is added every second
+	m.SetTime(time.Unix(1700000001, 0))
+	if res, _ := l.Allow(ctx, "a", limit); !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("after 1s = %+v, want one refilled token", res)
+	}
+	if res, _ := l.Allow(ctx, "a", limit); res.Allowed {
+		t.Fatal("bucket had more than the refilled token")
+	}
+
+	if ttl := m.TTL("ratelimit:a"); ttl <= 0 || ttl > 4*time.Second {
+		t.Fatalf("TTL of the bucket = %s, want it to expire once it is full again", ttl)
+	}
+}
+
+func TestTokenBucketIsCappedAtBurst(t *testing.T) {
+	l, m := newTestLimiter(t)
+	ctx := context.Background()
+	limit := Limit{Rate: 10, Burst: 2}
+
+	l.Allow(ctx, "a", limit)
+	m.SetTime(time.Unix(1700000100, 0))
+	res, err := l.Allow(ctx, "a", limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Remaining != 1 {
+		t.Fatalf("remaining after a long pause = %d, want burst - 1", res.Remaining)
+	}
+}
@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"api-service/internal/cruds"
+	"api-service/internal/gateway"
+	"api-service/internal/middleware"
+	"api-service/internal/openapi"
+	"net/http"
+)
+
+// gatewayPrefixes are handed to the gRPC gateway, it matches the patterns of gateway.Patterns itself
+var gatewayPrefixes = []string{"/v1/", "/v2/", "/tasks", "/tasks/"}
+
+type route struct {
+	pattern string
+	handler http.Handler
+}
+
+// legacy are the deprecated RPC-style routes, kept until clients move to /v1/tasks
+func legacy(u *cruds.CRUDOperations) []route {
+	return []route{
+		{"/create", middleware.Deprecated("/v1/tasks")(http.HandlerFunc(u.HandleCreate))},
+		{"/list", middleware.Deprecated("/v1/tasks")(http.HandlerFunc(u.HandleList))},
+		{"/delete", middleware.Deprecated("/v1/tasks/{id}")(http.HandlerFunc(u.HandleDelete))},
+		{"/done", middleware.Deprecated("/v1/tasks/{id}/done")(http.HandlerFunc(u.HandleDone))},
+	}
+}
+
+// New returns the mux of the API: the gateway, the deprecated routes, openapi.json and its docs
+func New(gw http.Handler, u *cruds.CRUDOperations) *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, p := range gatewayPrefixes {
+		mux.Handle(p, gw)
+	}
+	for _, rt := range legacy(u) {
+		mux.Handle(rt.pattern, rt.handler)
+	}
+	openapi.Register(mux)
+	return mux
+}
+
+// Patterns returns the routes of New that openapi.json documents, the gateway patterns and the deprecated routes
+func Patterns() []string {
+	patterns := gateway.Patterns()
+	for _, rt := range legacy(nil) {
+		patterns = append(patterns, rt.pattern)
+	}
+	return patterns
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var pathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// TestPatternsAreServed sends a request for every pattern to the mux of New, each must reach the handler
+// that serves it there and not the docs or a 404
+func TestPatternsAreServed(t *testing.T) {
+	mux := New(http.NotFoundHandler(), nil)
+
+	for _, p := range Patterns() {
+		method, path, ok := strings.Cut(p, " ")
+		if !ok {
+			method, path = http.MethodPost, p
+		}
+		r := httptest.NewRequest(method, pathParam.ReplaceAllString(path, "1"), nil)
+
+		_, pattern := mux.Handler(r)
+		if pattern != p && !slices.Contains(gatewayPrefixes, pattern) {
+			t.Errorf("%s is served by %q", p, pattern)
+		}
+	}
+}
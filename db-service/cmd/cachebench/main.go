@@ -0,0 +1,282 @@
+// cachebench compares the per-task cache of TaskManager with the single task_list blob it replaced.
+// Readers call List while writers mark tasks as done, which used to drop the whole cached list.
+// The stampede mode drops the cached list and sends -readers concurrent List calls to -replicas
+// TaskManagers at once, they should end up in a single database query.
+// It needs the Postgres and Redis of config.Load and removes the tasks it creates.
+//
+//	go run ./cmd/cachebench -mode task
+//	go run ./cmd/cachebench -mode blob
+//	go run ./cmd/cachebench -mode stampede -readers 100 -replicas 3
+package main
+
+import (
+	"context"
+	"database/sql"
+	"db-service/internal/cache"
+	"db-service/internal/config"
+	"db-service/internal/pkg/logger"
+	"db-service/internal/repository"
+	"db-service/internal/taskmanager"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	taskpb "task-api/taskpb/v1"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	benchHeader = "cachebench"
+	// the query of the old blob cache
+	blobListQuery = "SELECT id, header, body, isdone, version FROM tasks WHERE deleted_at IS NULL ORDER BY id"
+)
+
+func main() {
+	mode := flag.String("mode", "task", "task: TaskManager with per-task cache entries, blob: the old task_list blob, stampede: concurrent List misses")
+	duration := flag.Duration("duration", 10*time.Second, "how long to run")
+	readers := flag.Int("readers", 8, "concurrent List callers")
+	writers := flag.Int("writers", 2, "concurrent Done callers")
+	tasks := flag.Int("tasks", 200, "tasks to create before the run")
+	replicas := flag.Int("replicas", 1, "TaskManagers sharing Redis in the stampede mode")
+	flag.Parse()
+
+	cfg := config.Load()
+	db, err := sql.Open("postgres", cfg.DSN)
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+	defer db.Close()
+	repo := &countingRepo{TaskRepository: repository.NewPostgres(db)}
+	rdb := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr, Password: cfg.RedisPassword})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	ids, err := seed(ctx, db, *tasks)
+	if err != nil {
+		log.Fatalf("failed to create tasks: %v", err)
+	}
+	taskCache := cache.NewRedis(rdb, cfg.CacheNamespace, cfg.CacheTTL)
+	defer cleanup(ctx, db, rdb, taskCache)
+
+	if *mode == "stampede" {
+		stampede(ctx, repo, taskCache, cfg.MaxBatchSize, *readers, *replicas)
+		return
+	}
+
+	tm := taskmanager.NewTaskManager(repo, taskCache, logger.NewNopLogger(), cfg.MaxBatchSize)
+	var b bench
+	switch *mode {
+	case "task":
+		b = taskBench{tm: tm}
+	case "blob":
+		b = blobBench{taskBench: taskBench{tm: tm}, db: db, rdb: rdb, lists: &repo.lists}
+	default:
+		log.Fatalf("unknown mode %q", *mode)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		latencies []time.Duration
+		writes    atomic.Int64
+		errs      atomic.Int64
+	)
+	deadline := time.Now().Add(*duration)
+
+	for range *readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local []time.Duration
+			for time.Now().Before(deadline) {
+				start := time.Now()
+				if err := b.list(ctx); err != nil {
+					errs.Add(1)
+					continue
+				}
+				local = append(local, time.Since(start))
+			}
+			mu.Lock()
+			latencies = append(latencies, local...)
+			mu.Unlock()
+		}()
+	}
+	for range *writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				if err := b.done(ctx, ids[rand.IntN(len(ids))]); err != nil {
+					errs.Add(1)
+					continue
+				}
+				writes.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(latencies)
+	fmt.Printf("mode=%s tasks=%d readers=%d writers=%d duration=%s\n", *mode, *tasks, *readers, *writers, *duration)
+	fmt.Printf("list: %d calls, %.0f/s, p50 %s, p99 %s\n",
+		len(latencies), float64(len(latencies))/duration.Seconds(), percentile(latencies, 0.5), percentile(latencies, 0.99))
+	fmt.Printf("done: %d calls, %.0f/s\n", writes.Load(), float64(writes.Load())/duration.Seconds())
+	fmt.Printf("list database queries: %d\n", repo.lists.Load())
+	fmt.Printf("errors: %d\n", errs.Load())
+}
+
+type bench interface {
+	list(ctx context.Context) error
+	done(ctx context.Context, id string) error
+}
+
+type taskBench struct {
+	tm *taskmanager.TaskManager
+}
+
+func (b taskBench) list(ctx context.Context) error {
+	_, err := b.tm.List(ctx, &taskpb.TaskID{})
+	return err
+}
+
+func (b taskBench) done(ctx context.Context, id string) error {
+	_, err := b.tm.Done(ctx, &taskpb.TaskID{ID: id})
+	return err
+}
+
+// blobBench is the caching TaskManager had before: List caches all tasks as one JSON value for a minute
+// and every write deletes it. Writes go through TaskManager so both modes pay the same for them
+type blobBench struct {
+	taskBench
+	db    *sql.DB
+	rdb   *redis.Client
+	lists *atomic.Int64
+}
+
+func (b blobBench) list(ctx context.Context) error {
+	if val, err := b.rdb.Get(ctx, "task_list").Bytes(); err == nil {
+		var cached taskpb.TaskList
+		if err := protojson.Unmarshal(val, &cached); err == nil {
+			return nil
+		}
+	}
+
+	b.lists.Add(1)
+	rows, err := b.db.QueryContext(ctx, blobListQuery)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var list taskpb.TaskList
+	for rows.Next() {
+		var t taskpb.Task
+		if err := rows.Scan(&t.ID, &t.Header, &t.Body, &t.IsDone, &t.Version); err != nil {
+			return err
+		}
+		list.Tasks = append(list.Tasks, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	data, err := protojson.Marshal(&list)
+	if err != nil {
+		return err
+	}
+	return b.rdb.Set(ctx, "task_list", data, time.Minute).Err()
+}
+
+func (b blobBench) done(ctx context.Context, id string) error {
+	if err := b.taskBench.done(ctx, id); err != nil {
+		return err
+	}
+	return b.rdb.Del(ctx, "task_list").Err()
+}
+
+// stampede sends concurrent List calls right after the cached list was dropped
+func stampede(ctx context.Context, repo *countingRepo, taskCache *cache.Redis, maxBatchSize, calls, replicas int) {
+	tms := make([]*taskmanager.TaskManager, replicas)
+	for i := range tms {
+		tms[i] = taskmanager.NewTaskManager(repo, taskCache, logger.NewNopLogger(), maxBatchSize)
+	}
+	if err := taskCache.Flush(ctx); err != nil {
+		log.Fatalf("failed to drop cached list: %v", err)
+	}
+	repo.lists.Store(0)
+
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  atomic.Int64
+	)
+	for i := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if _, err := tms[i%replicas].List(ctx, &taskpb.TaskID{}); err != nil {
+				errs.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	fmt.Printf("%d concurrent List misses on %d replicas: %d database queries, %d errors\n", calls, replicas, repo.lists.Load(), errs.Load())
+}
+
+// countingRepo counts the List calls that reach Postgres, whatever query the repository uses
+type countingRepo struct {
+	repository.TaskRepository
+	lists atomic.Int64
+}
+
+func (r *countingRepo) List(ctx context.Context) ([]*taskpb.Task, error) {
+	r.lists.Add(1)
+	return r.TaskRepository.List(ctx)
+}
+
+func seed(ctx context.Context, db *sql.DB, n int) ([]string, error) {
+	ids := make([]string, 0, n)
+	for i := range n {
+		var id string
+		err := db.QueryRowContext(ctx, "INSERT INTO tasks(header, body) VALUES ($1, $2) RETURNING id", benchHeader, "task "+strconv.Itoa(i)).Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// cleanup removes the bench tasks with their history and drops the cache, TaskManager would serve them otherwise
+func cleanup(ctx context.Context, db *sql.DB, rdb *redis.Client, taskCache *cache.Redis) {
+	if _, err := db.ExecContext(ctx, "DELETE FROM task_events WHERE task_id IN (SELECT id FROM tasks WHERE header = $1);", benchHeader); err != nil {
+		log.Printf("failed to delete bench history: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "DELETE FROM tasks WHERE header = $1;", benchHeader); err != nil {
+		log.Printf("failed to delete bench tasks: %v", err)
+	}
+	if err := rdb.Del(ctx, "task_list").Err(); err != nil {
+		log.Printf("failed to drop cached blob: %v", err)
+	}
+	if err := taskCache.Flush(ctx); err != nil {
+		log.Printf("failed to drop cache: %v", err)
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(float64(len(sorted)-1)*p)]
+}
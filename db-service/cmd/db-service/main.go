@@ -1,36 +1,76 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	messagepb "db-service/api/proto"
+	"db-service/internal/cache"
+	"db-service/internal/config"
+	"db-service/internal/deadline"
+	dbhealth "db-service/internal/health"
+	"db-service/internal/idempotency"
 	"db-service/internal/pkg/logger"
+	"db-service/internal/repository"
 	"db-service/internal/taskmanager"
 	"log"
 	"net"
+	"net/http"
+	taskpb "task-api/taskpb/v1"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
-	dsn := "host=127.0.0.1 port=5432 user=dude password=pass dbname=tasksdb sslmode=disable"
-	db, err := sql.Open("postgres", dsn)
-	if err != nil {
-		log.Fatalf("1failed to connect to database: %v", err)
-		return
+	cfg := config.Load()
+
+	var (
+		repo repository.TaskRepository
+		ping func(context.Context) error
+	)
+	switch cfg.Driver {
+	case "pq":
+		db, err := sql.Open("postgres", cfg.DSN)
+		if err != nil {
+			log.Fatalf("1failed to connect to database: %v", err)
+			return
+		}
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+		prometheus.MustRegister(collectors.NewDBStatsCollector(db, "tasksdb"))
+
+		err = db.Ping()
+		if err != nil {
+			log.Fatalf("2failed to connect to database: %v", err)
+		}
+		repo, ping = repository.NewPostgres(db), db.PingContext
+	case "pgx":
+		pool, err := repository.NewPgxPool(context.Background(), cfg.DSN, cfg.MaxOpenConns, cfg.ConnMaxLifetime, cfg.ConnMaxIdleTime)
+		if err != nil {
+			log.Fatalf("failed to connect to database: %v", err)
+		}
+		prometheus.MustRegister(repository.NewPgxStatsCollector(pool, "tasksdb"))
+
+		if err := pool.Ping(context.Background()); err != nil {
+			log.Fatalf("failed to connect to database: %v", err)
+		}
+		repo, ping = repository.NewPgx(pool), pool.Ping
+	default:
+		log.Fatalf("unknown driver %q, use pq or pgx", cfg.Driver)
 	}
 
 	rdb := redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "redkaPass",
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
 	})
 
-	err = db.Ping()
-	if err != nil {
-		log.Fatalf("2failed to connect to database: %v", err)
-	}
-
-	lis, err := net.Listen("tcp", ":8081")
+	lis, err := net.Listen("tcp", cfg.GRPCAddr)
 	if err != nil {
 		log.Fatalln("cant listen port", err)
 	}
@@ -39,11 +79,54 @@ func main() {
 	defer logger.Close()
 	logger.Logger().Info().Msg("start-logging db-service!!!")
 
-	server := grpc.NewServer()
+	idempotencyStore := idempotency.NewStore(rdb, cfg.CacheNamespace, cfg.IdempotencyTTL, logger)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		deadline.UnaryServerInterceptor(logger),
+		idempotencyStore.UnaryServerInterceptor(
+			taskpb.TaskService_Create_FullMethodName,
+			taskpb.TaskService_Update_FullMethodName,
+			taskpb.TaskService_Done_FullMethodName,
+			taskpb.TaskService_Delete_FullMethodName,
+			taskpb.TaskService_Restore_FullMethodName,
+			taskpb.TaskService_BatchCreate_FullMethodName,
+			taskpb.TaskService_BatchDone_FullMethodName,
+			taskpb.TaskService_BatchDelete_FullMethodName,
+		),
+	))
+
+	var taskCache cache.Cache
+	switch cfg.Cache {
+	case "redis":
+		taskCache = cache.NewBreaker(cache.NewRedis(rdb, cfg.CacheNamespace, cfg.CacheTTL), cfg.CacheBreakerFailures, cfg.CacheBreakerCooldown, logger)
+	case "memory":
+		taskCache = cache.NewLRU(cfg.CacheSize, cfg.CacheTTL)
+	case "none":
+		taskCache = cache.NewNop()
+	default:
+		log.Fatalf("unknown cache %q, use redis, memory or none", cfg.Cache)
+	}
+
+	tm := taskmanager.NewTaskManager(repo, taskCache, logger, cfg.MaxBatchSize)
+	taskpb.RegisterTaskServiceServer(server, tm)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	checker := dbhealth.NewChecker(ping, healthServer, cfg.HealthInterval, cfg.HealthTimeout, logger, taskpb.TaskService_ServiceDesc.ServiceName)
+	go checker.Run(context.Background())
+
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+	metricsMux.Handle("/readyz", checker.ReadyHandler())
+	go func() {
+		log.Printf("metrics listening on %s", cfg.MetricsAddr)
+		if err := http.ListenAndServe(cfg.MetricsAddr, metricsMux); err != nil {
+			log.Fatalf("failed to serve metrics: %v", err)
+		}
+	}()
 
-	messagepb.RegisterTaskServiceServer(server, taskmanager.NewTaskManager(db, rdb, logger))
+	go tm.RunPurger(context.Background(), cfg.TrashRetention, cfg.PurgeInterval)
 
-	log.Println("gRPC server listening on :8081")
+	log.Printf("gRPC server listening on %s", cfg.GRPCAddr)
 	if err := server.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"db-service/internal/pkg/logger"
+	"errors"
+	"sync"
+	pb "task-api/taskpb/v1"
+	"time"
+)
+
+// ErrOpen is returned by Breaker.Gen while the cache is bypassed, so the list read from the database is not cached
+var ErrOpen = errors.New("cache circuit breaker is open")
+
+// Shared is a cache shared by all replicas. A replica that may have lost writes marks it dirty, so the others
+// flush it too if that replica stops before it has flushed it
+type Shared interface {
+	Cache
+	MarkDirty(ctx context.Context) error
+	Dirty(ctx context.Context) (bool, error)
+}
+
+// Breaker bypasses a failing cache. It opens after failures errors in a row and lets one call through every cooldown
+// to check if the cache is back. A failed write opens it at once, the cache could keep the old task otherwise.
+// Writes made while it is open are lost for the cache, so the cache is marked dirty and flushed before the breaker
+// closes. It starts open because a new process can't know what its predecessor lost. While it is closed it checks
+// the dirty mark every cooldown, for replicas that stopped between marking and flushing
+type Breaker struct {
+	next     Shared
+	failures int
+	cooldown time.Duration
+	logger   *logger.KafkaLogger
+	mu       sync.Mutex
+	errs     int
+	open     bool
+	// the next probe while open, the next dirty check while closed
+	checkAt time.Time
+	// writes skipped while open, a probe doesn't close the breaker if writes were skipped during its flush
+	skipped int64
+}
+
+func NewBreaker(next Shared, failures int, cooldown time.Duration, logger *logger.KafkaLogger) *Breaker {
+	return &Breaker{
+		next:     next,
+		failures: failures,
+		cooldown: cooldown,
+		logger:   logger,
+		open:     true,
+	}
+}
+
+// allow reports if a call may use the cache. The first caller after checkAt probes the cache or checks the dirty mark,
+// the others go on without waiting for it
+func (b *Breaker) allow(ctx context.Context) bool {
+	b.mu.Lock()
+	if time.Now().Before(b.checkAt) {
+		open := b.open
+		b.mu.Unlock()
+		return !open
+	}
+	b.checkAt = time.Now().Add(b.cooldown)
+	open := b.open
+	b.mu.Unlock()
+
+	if open {
+		return b.recover(ctx)
+	}
+
+	// on an error the call itself decides, a failed write opens the breaker
+	dirty, err := b.next.Dirty(ctx)
+	b.done(ctx, err, false)
+	if err == nil && dirty {
+		b.logger.Logger().Warn().Msg("cache was marked dirty by another replica, flushing it")
+		if err := b.flush(ctx); err != nil {
+			// the cache stays stale, open at once like for a lost write
+			b.done(ctx, err, true)
+			return false
+		}
+	}
+	return true
+}
+
+// recover marks the cache dirty and flushes it, the breaker closes if both succeed
+func (b *Breaker) recover(ctx context.Context) bool {
+	b.mu.Lock()
+	skipped := b.skipped
+	b.mu.Unlock()
+
+	if err := b.next.MarkDirty(ctx); err != nil {
+		b.logger.Logger().Warn().Err(err).Msg("cache is still unavailable, failed to mark it dirty")
+		return false
+	}
+	if err := b.flush(ctx); err != nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.skipped != skipped {
+		// a list cached during the flush could miss these writes, flush again on the next call
+		b.checkAt = time.Time{}
+		return false
+	}
+	b.open = false
+	b.errs = 0
+	b.skipped = 0
+	b.logger.Logger().Info().Msg("flushed cache, circuit breaker closed")
+	return true
+}
+
+func (b *Breaker) flush(ctx context.Context) error {
+	err := b.next.Flush(ctx)
+	if err != nil {
+		b.logger.Logger().Warn().Err(err).Msg("failed to flush cache")
+	}
+	return err
+}
+
+// done records the result of a call, write is true if a failed call lost a write
+func (b *Breaker) done(ctx context.Context, err error, write bool) {
+	if err != nil && ctx.Err() != nil {
+		// canceled by the caller, says nothing about the cache
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.errs = 0
+		return
+	}
+
+	b.errs++
+	if !b.open && (write || b.errs >= b.failures) {
+		b.logger.Logger().Warn().Err(err).Int("errors", b.errs).Msg("cache circuit breaker opened, bypassing the cache")
+		b.open = true
+		b.checkAt = time.Now().Add(b.cooldown)
+	}
+}
+
+// skip records a write dropped while the breaker is open
+func (b *Breaker) skip(write bool) {
+	if !write {
+		return
+	}
+	b.mu.Lock()
+	b.skipped++
+	b.mu.Unlock()
+}
+
+func (b *Breaker) PutTasks(ctx context.Context, entries []Entry, write bool) error {
+	if !b.allow(ctx) {
+		b.skip(write)
+		return nil
+	}
+	err := b.next.PutTasks(ctx, entries, write)
+	b.done(ctx, err, write)
+	return err
+}
+
+func (b *Breaker) GetTask(ctx context.Context, id string) (*pb.Task, bool, error) {
+	if !b.allow(ctx) {
+		return nil, false, nil
+	}
+	t, ok, err := b.next.GetTask(ctx, id)
+	b.done(ctx, err, false)
+	return t, ok, err
+}
+
+func (b *Breaker) List(ctx context.Context) ([]*pb.Task, bool, error) {
+	if !b.allow(ctx) {
+		return nil, false, nil
+	}
+	tasks, ok, err := b.next.List(ctx)
+	b.done(ctx, err, false)
+	return tasks, ok, err
+}
+
+func (b *Breaker) Gen(ctx context.Context) (int64, error) {
+	if !b.allow(ctx) {
+		return 0, ErrOpen
+	}
+	gen, err := b.next.Gen(ctx)
+	b.done(ctx, err, false)
+	return gen, err
+}
+
+func (b *Breaker) SetList(ctx context.Context, tasks []*pb.Task, gen int64) error {
+	if !b.allow(ctx) {
+		return nil
+	}
+	err := b.next.SetList(ctx, tasks, gen)
+	b.done(ctx, err, false)
+	return err
+}
+
+func (b *Breaker) LockList(ctx context.Context) (func(), bool, error) {
+	if !b.allow(ctx) {
+		return func() {}, true, nil
+	}
+	unlock, ok, err := b.next.LockList(ctx)
+	b.done(ctx, err, false)
+	return unlock, ok, err
+}
+
+func (b *Breaker) Flush(ctx context.Context) error {
+	err := b.next.Flush(ctx)
+	b.done(ctx, err, false)
+	return err
+}
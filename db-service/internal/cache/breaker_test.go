@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"context"
+	"db-service/internal/pkg/logger"
+	"errors"
+	"sync"
+	pb "task-api/taskpb/v1"
+	"testing"
+	"time"
+)
+
+const testCooldown = 10 * time.Millisecond
+
+var errDown = errors.New("cache is down")
+
+// fakeShared is an LRU with the dirty mark of Redis that fails every call while down is set
+type fakeShared struct {
+	*LRU
+	mu      sync.Mutex
+	down    bool
+	dirty   bool
+	flushes int
+}
+
+func newFakeShared() *fakeShared {
+	return &fakeShared{LRU: NewLRU(100, time.Minute)}
+}
+
+func (f *fakeShared) setDown(down bool) {
+	f.mu.Lock()
+	f.down = down
+	f.mu.Unlock()
+}
+
+func (f *fakeShared) err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return errDown
+	}
+	return nil
+}
+
+func (f *fakeShared) GetTask(ctx context.Context, id string) (*pb.Task, bool, error) {
+	if err := f.err(); err != nil {
+		return nil, false, err
+	}
+	return f.LRU.GetTask(ctx, id)
+}
+
+func (f *fakeShared) PutTasks(ctx context.Context, entries []Entry, write bool) error {
+	if err := f.err(); err != nil {
+		return err
+	}
+	return f.LRU.PutTasks(ctx, entries, write)
+}
+
+func (f *fakeShared) MarkDirty(ctx context.Context) error {
+	if err := f.err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.dirty = true
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *fakeShared) Dirty(ctx context.Context) (bool, error) {
+	if err := f.err(); err != nil {
+		return false, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.dirty, nil
+}
+
+func (f *fakeShared) Flush(ctx context.Context) error {
+	if err := f.err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.dirty = false
+	f.flushes++
+	f.mu.Unlock()
+	return f.LRU.Flush(ctx)
+}
+
+// putStale caches a task behind the breaker, like a replica whose write was lost left it
+func putStale(t *testing.T, f *fakeShared) {
+	t.Helper()
+	if err := f.LRU.PutTasks(context.Background(), []Entry{{Task: &pb.Task{ID: "1", Header: "stale", Version: 1}}}, true); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func wantFlushed(t *testing.T, b *Breaker, f *fakeShared, flushes int) {
+	t.Helper()
+	if _, ok, err := b.GetTask(context.Background(), "1"); ok || err != nil {
+		t.Fatalf("GetTask = %v, %v, want the stale task flushed", ok, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.flushes != flushes || f.dirty {
+		t.Fatalf("flushes = %d, dirty = %v, want %d flushes and a clean cache", f.flushes, f.dirty, flushes)
+	}
+}
+
+func TestBreakerFlushesBeforeFirstUse(t *testing.T) {
+	f := newFakeShared()
+	putStale(t, f)
+	b := NewBreaker(f, 2, testCooldown, logger.NewNopLogger())
+
+	wantFlushed(t, b, f, 1)
+}
+
+func TestBreakerFlushesWhenItCloses(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeShared()
+	b := NewBreaker(f, 2, testCooldown, logger.NewNopLogger())
+	wantFlushed(t, b, f, 1)
+
+	// reads fail, no write of this replica is lost, another one could have lost some
+	f.setDown(true)
+	for range 2 {
+		b.GetTask(ctx, "1")
+	}
+	f.setDown(false)
+	putStale(t, f)
+
+	if _, ok, _ := b.GetTask(ctx, "1"); ok {
+		t.Fatal("open breaker read from the cache")
+	}
+	time.Sleep(2 * testCooldown)
+	wantFlushed(t, b, f, 2)
+}
+
+func TestBreakerFlushesCacheMarkedDirty(t *testing.T) {
+	f := newFakeShared()
+	b := NewBreaker(f, 2, testCooldown, logger.NewNopLogger())
+	wantFlushed(t, b, f, 1)
+
+	// another replica marked the cache and stopped before it flushed it
+	putStale(t, f)
+	if err := f.MarkDirty(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(2 * testCooldown)
+	wantFlushed(t, b, f, 2)
+}
+
+func TestBreakerOpensOnLostWrite(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeShared()
+	b := NewBreaker(f, 2, testCooldown, logger.NewNopLogger())
+	wantFlushed(t, b, f, 1)
+	putStale(t, f)
+
+	f.setDown(true)
+	if err := b.PutTasks(ctx, []Entry{{Task: &pb.Task{ID: "1", Header: "new", Version: 2}}}, true); err == nil {
+		t.Fatal("PutTasks succeeded on a failing cache")
+	}
+	f.setDown(false)
+
+	// one lost write is enough, the stale version must not be served
+	if _, ok, _ := b.GetTask(ctx, "1"); ok {
+		t.Fatal("breaker served a task whose write was lost")
+	}
+	time.Sleep(2 * testCooldown)
+	wantFlushed(t, b, f, 2)
+}
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"context"
+	pb "task-api/taskpb/v1"
+)
+
+// Cache keeps tasks and the List of TaskManager. Every task is cached on its own, the list is an index of ids
+// ordered by id, so writes update single entries instead of dropping the whole list.
+type Cache interface {
+	// PutTasks caches tasks unless the cached copy has a newer version. Deleted entries are kept as tombstones and
+	// removed from the list. write is false for tasks that were only read from the database
+	PutTasks(ctx context.Context, entries []Entry, write bool) error
+	// GetTask returns a cached task, ok is false if it is not cached or deleted
+	GetTask(ctx context.Context, id string) (task *pb.Task, ok bool, err error)
+	// List returns the cached list, ok is false if the list or one of its tasks is not cached
+	List(ctx context.Context) (tasks []*pb.Task, ok bool, err error)
+	// Gen returns the number of writes so far, read it before the list is queried from the database
+	Gen(ctx context.Context) (int64, error)
+	// SetList caches a list read from the database. It is dropped if a write happened since gen was read,
+	// the list could miss it
+	SetList(ctx context.Context, tasks []*pb.Task, gen int64) error
+	// LockList is taken by the caller that rebuilds the list, ok is false if someone else is rebuilding it
+	LockList(ctx context.Context) (unlock func(), ok bool, err error)
+	// Flush drops every cached task and the list
+	Flush(ctx context.Context) error
+}
+
+// Entry is a task written to the cache
+type Entry struct {
+	Task    *pb.Task
+	Deleted bool
+}
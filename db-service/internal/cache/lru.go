@@ -0,0 +1,187 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	pb "task-api/taskpb/v1"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// LRU keeps up to size tasks in process memory. Every replica has its own copy, so writes of other replicas
+// are only seen after ttl, use it for a single replica or in development
+type LRU struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	order *list.List
+	items map[string]*list.Element
+	// ids of the cached list, nil if the list is not cached
+	index        map[string]int64
+	indexExpires time.Time
+	gen          int64
+}
+
+type lruItem struct {
+	entry   Entry
+	expires time.Time
+}
+
+func NewLRU(size int, ttl time.Duration) *LRU {
+	return &LRU{
+		size:  size,
+		ttl:   ttl,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *LRU) PutTasks(ctx context.Context, entries []Entry, write bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range entries {
+		c.put(e)
+		if c.index != nil {
+			if e.Deleted {
+				delete(c.index, e.Task.ID)
+			} else {
+				c.index[e.Task.ID] = idOrder(e.Task.ID)
+			}
+		}
+	}
+	if write {
+		c.gen++
+	}
+	return nil
+}
+
+// put stores e unless the cached task has a newer version, like putTaskScript of Redis
+func (c *LRU) put(e Entry) {
+	now := time.Now()
+	if el, ok := c.items[e.Task.ID]; ok {
+		it := el.Value.(*lruItem)
+		if it.expires.After(now) && it.entry.Task.Version > e.Task.Version {
+			return
+		}
+		it.entry = Entry{Task: proto.Clone(e.Task).(*pb.Task), Deleted: e.Deleted}
+		it.expires = now.Add(c.ttl)
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[e.Task.ID] = c.order.PushFront(&lruItem{
+		entry:   Entry{Task: proto.Clone(e.Task).(*pb.Task), Deleted: e.Deleted},
+		expires: now.Add(c.ttl),
+	})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruItem).entry.Task.ID)
+	}
+}
+
+// get returns a cached task that is not expired or deleted
+func (c *LRU) get(id string) (*pb.Task, bool) {
+	el, ok := c.items[id]
+	if !ok {
+		return nil, false
+	}
+	it := el.Value.(*lruItem)
+	if !it.expires.After(time.Now()) {
+		c.order.Remove(el)
+		delete(c.items, id)
+		return nil, false
+	}
+	if it.entry.Deleted {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return proto.Clone(it.entry.Task).(*pb.Task), true
+}
+
+func (c *LRU) GetTask(ctx context.Context, id string) (*pb.Task, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t, ok := c.get(id)
+	return t, ok, nil
+}
+
+func (c *LRU) List(ctx context.Context) ([]*pb.Task, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.index == nil || !c.indexExpires.After(time.Now()) {
+		c.index = nil
+		return nil, false, nil
+	}
+
+	ids := make([]string, 0, len(c.index))
+	for id := range c.index {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return c.index[ids[i]] < c.index[ids[j]]
+	})
+
+	tasks := make([]*pb.Task, 0, len(ids))
+	for _, id := range ids {
+		t, ok := c.get(id)
+		if !ok {
+			return nil, false, nil
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, true, nil
+}
+
+func (c *LRU) Gen(ctx context.Context) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.gen, nil
+}
+
+func (c *LRU) SetList(ctx context.Context, tasks []*pb.Task, gen int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gen != gen {
+		return nil
+	}
+
+	index := make(map[string]int64, len(tasks))
+	for _, t := range tasks {
+		c.put(Entry{Task: t})
+		index[t.ID] = idOrder(t.ID)
+	}
+	c.index = index
+	c.indexExpires = time.Now().Add(c.ttl)
+	return nil
+}
+
+func (c *LRU) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	clear(c.items)
+	c.index = nil
+	c.gen++
+	return nil
+}
+
+// LockList always succeeds, concurrent List calls of one process already share a single load
+func (c *LRU) LockList(ctx context.Context) (func(), bool, error) {
+	return func() {}, true, nil
+}
+
+func idOrder(id string) int64 {
+	n, _ := strconv.ParseInt(id, 10, 64)
+	return n
+}
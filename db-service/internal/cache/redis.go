@@ -0,0 +1,331 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	pb "task-api/taskpb/v1"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Every task is cached in its own hash under taskKeyPrefix+id, the sorted set taskIndexKey holds the ids of
+// the list ordered by id. All keys start with the prefix of NewRedis.
+const (
+	taskKeyPrefix = "task:"
+	taskIndexKey  = "tasks:index"
+	// incremented by every write, a list read from the database before a write doesn't replace the index
+	taskGenKey = "tasks:gen"
+	// the index always holds indexSentinel so that an empty list is cached too
+	indexSentinel = "-"
+	// held by the replica that rebuilds the list
+	listLockKey = "tasks:index:lock"
+	listLockTTL = 5 * time.Second
+	// keys deleted at once by Flush
+	flushBatch = 500
+	// set while the cache may miss writes of a replica, it is only deleted by Flush
+	dirtyKey = "tasks:dirty"
+)
+
+// SchemaVersion is a part of every key. Bump it when the cached fields of a task change, the entries of
+// the previous version are not read any more and expire
+const SchemaVersion = 2
+
+// putTaskScript writes a task hash unless the cached one has a newer version, so a slow write can't overwrite
+// a newer one. Deleted tasks stay as tombstones with their version until they expire. If the index is cached
+// the task is added to or removed from it, a missing index is rebuilt by the next List. Writes bump taskGenKey,
+// List rebuilds don't.
+var putTaskScript = redis.NewScript(`
+local cached = tonumber(redis.call('HGET', KEYS[1], 'version'))
+if cached and cached > tonumber(ARGV[5]) then
+	return 0
+end
+
+redis.call('HSET', KEYS[1], 'id', ARGV[1], 'header', ARGV[2], 'body', ARGV[3], 'isdone', ARGV[4], 'version', ARGV[5], 'deleted', ARGV[6], 'tags', ARGV[9])
+redis.call('EXPIRE', KEYS[1], ARGV[7])
+
+if redis.call('EXISTS', KEYS[2]) == 1 then
+	if ARGV[6] == '1' then
+		redis.call('ZREM', KEYS[2], ARGV[1])
+	else
+		redis.call('ZADD', KEYS[2], ARGV[1], ARGV[1])
+	end
+	redis.call('EXPIRE', KEYS[2], ARGV[7])
+end
+if ARGV[8] == '1' then
+	redis.call('INCR', KEYS[3])
+end
+return 1
+`)
+
+// Redis is shared by all db-service replicas
+type Redis struct {
+	rdb    *redis.Client
+	ttl    time.Duration
+	prefix string
+}
+
+// NewRedis keeps the keys under namespace:v<SchemaVersion>:, services that share Redis need different namespaces
+func NewRedis(rdb *redis.Client, namespace string, ttl time.Duration) *Redis {
+	return &Redis{
+		rdb:    rdb,
+		ttl:    ttl,
+		prefix: namespace + ":v" + strconv.Itoa(SchemaVersion) + ":",
+	}
+}
+
+func (c *Redis) taskKey(id string) string {
+	return c.prefix + taskKeyPrefix + id
+}
+
+func (c *Redis) key(name string) string {
+	return c.prefix + name
+}
+
+// PutTasks writes entries in one pipeline
+func (c *Redis) PutTasks(ctx context.Context, entries []Entry, write bool) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	tags := make([]string, len(entries))
+	for i, e := range entries {
+		data, err := json.Marshal(e.Task.Tags)
+		if err != nil {
+			return fmt.Errorf("marshal tags error %s", err)
+		}
+		tags[i] = string(data)
+	}
+
+	run := func() error {
+		pipe := c.rdb.Pipeline()
+		for i, e := range entries {
+			putTaskScript.EvalSha(ctx, pipe, []string{c.taskKey(e.Task.ID), c.key(taskIndexKey), c.key(taskGenKey)},
+				e.Task.ID, e.Task.Header, e.Task.Body, strconv.FormatBool(e.Task.IsDone), e.Task.Version, flag(e.Deleted), int(c.ttl.Seconds()), flag(write), tags[i])
+		}
+		_, err := pipe.Exec(ctx)
+		return err
+	}
+
+	err := run()
+	if redis.HasErrorPrefix(err, "NOSCRIPT") {
+		if err := putTaskScript.Load(ctx, c.rdb).Err(); err != nil {
+			return err
+		}
+		err = run()
+	}
+	return err
+}
+
+func (c *Redis) List(ctx context.Context) (tasks []*pb.Task, ok bool, err error) {
+	ids, err := c.rdb.ZRange(ctx, c.key(taskIndexKey), 0, -1).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, false, err
+	}
+
+	pipe := c.rdb.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(ids))
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != indexSentinel {
+			keys = append(keys, c.taskKey(id))
+			cmds = append(cmds, pipe.HGetAll(ctx, keys[len(keys)-1]))
+		}
+	}
+	if len(cmds) == 0 {
+		return nil, true, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, false, err
+	}
+
+	tasks = make([]*pb.Task, 0, len(cmds))
+	var invalid []string
+	for i, cmd := range cmds {
+		t, err := taskFromHash(cmd.Val())
+		if err != nil {
+			invalid = append(invalid, keys[i])
+			continue
+		}
+		if t == nil {
+			return nil, false, nil
+		}
+		tasks = append(tasks, t)
+	}
+	if len(invalid) > 0 {
+		return nil, false, c.rdb.Del(ctx, invalid...).Err()
+	}
+	return tasks, true, nil
+}
+
+func (c *Redis) Gen(ctx context.Context) (int64, error) {
+	gen, err := c.rdb.Get(ctx, c.key(taskGenKey)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return gen, err
+}
+
+// SetList writes the tasks first, then replaces the index in a WATCH transaction on taskGenKey
+func (c *Redis) SetList(ctx context.Context, tasks []*pb.Task, gen int64) error {
+	entries := make([]Entry, 0, len(tasks))
+	members := make([]redis.Z, 0, len(tasks)+1)
+	members = append(members, redis.Z{Score: -1, Member: indexSentinel})
+	for _, t := range tasks {
+		entries = append(entries, Entry{Task: t})
+		id, _ := strconv.ParseFloat(t.ID, 64)
+		members = append(members, redis.Z{Score: id, Member: t.ID})
+	}
+	if err := c.PutTasks(ctx, entries, false); err != nil {
+		return err
+	}
+
+	err := c.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		cur, err := tx.Get(ctx, c.key(taskGenKey)).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if cur != gen {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, c.key(taskIndexKey))
+			pipe.ZAdd(ctx, c.key(taskIndexKey), members...)
+			pipe.Expire(ctx, c.key(taskIndexKey), c.ttl)
+			return nil
+		})
+		return err
+	}, c.key(taskGenKey))
+	if errors.Is(err, redis.TxFailedErr) {
+		return nil
+	}
+	return err
+}
+
+func (c *Redis) GetTask(ctx context.Context, id string) (*pb.Task, bool, error) {
+	fields, err := c.rdb.HGetAll(ctx, c.taskKey(id)).Result()
+	if err != nil || len(fields) == 0 {
+		return nil, false, err
+	}
+	t, err := taskFromHash(fields)
+	if err != nil {
+		return nil, false, c.rdb.Del(ctx, c.taskKey(id)).Err()
+	}
+	return t, t != nil, nil
+}
+
+func flag(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// taskFromHash returns nil for a deleted task and an error if the hash misses a field or has an invalid one,
+// such entries are deleted by the callers
+func taskFromHash(fields map[string]string) (*pb.Task, error) {
+	for _, f := range []string{"id", "header", "body", "isdone", "version", "deleted", "tags"} {
+		if _, ok := fields[f]; !ok {
+			return nil, fmt.Errorf("cached task has no %s field", f)
+		}
+	}
+	isDone, err := strconv.ParseBool(fields["isdone"])
+	if err != nil {
+		return nil, fmt.Errorf("cached task has invalid isdone %q", fields["isdone"])
+	}
+	version, err := strconv.ParseInt(fields["version"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("cached task has invalid version %q", fields["version"])
+	}
+	// tags are a JSON array, null for a task without tags
+	var tags []string
+	if err := json.Unmarshal([]byte(fields["tags"]), &tags); err != nil {
+		return nil, fmt.Errorf("cached task has invalid tags %q", fields["tags"])
+	}
+	switch fields["deleted"] {
+	case "1":
+		return nil, nil
+	case "0":
+	default:
+		return nil, fmt.Errorf("cached task has invalid deleted %q", fields["deleted"])
+	}
+	return &pb.Task{
+		ID:      fields["id"],
+		Header:  fields["header"],
+		Body:    fields["body"],
+		IsDone:  isDone,
+		Version: version,
+		Tags:    tags,
+	}, nil
+}
+
+// MarkDirty records in Redis that the cache may be stale, every replica flushes it until Flush succeeds
+func (c *Redis) MarkDirty(ctx context.Context) error {
+	return c.rdb.Set(ctx, c.key(dirtyKey), "1", 0).Err()
+}
+
+func (c *Redis) Dirty(ctx context.Context) (bool, error) {
+	n, err := c.rdb.Exists(ctx, c.key(dirtyKey)).Result()
+	return n > 0, err
+}
+
+// Flush deletes the task hashes with SCAN, so it doesn't block Redis like KEYS would. taskGenKey is bumped
+// so that a list read from the database before the flush is not cached, the dirty mark is cleared
+func (c *Redis) Flush(ctx context.Context) error {
+	iter := c.rdb.Scan(ctx, 0, c.taskKey("*"), flushBatch).Iterator()
+	keys := make([]string, 0, flushBatch)
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == flushBatch {
+			if err := c.rdb.Unlink(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(keys) > 0 {
+			pipe.Unlink(ctx, keys...)
+		}
+		pipe.Del(ctx, c.key(taskIndexKey), c.key(dirtyKey))
+		pipe.Incr(ctx, c.key(taskGenKey))
+		return nil
+	})
+	return err
+}
+
+// unlockScript deletes the lock only if it still holds our token, it could have expired and been taken by another replica
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// LockList takes listLockKey for listLockTTL, it is shared by all replicas
+func (c *Redis) LockList(ctx context.Context) (func(), bool, error) {
+	token := newLockToken()
+	ok, err := c.rdb.SetNX(ctx, c.key(listLockKey), token, listLockTTL).Result()
+	if err != nil || !ok {
+		return func() {}, false, err
+	}
+	return func() {
+		unlockScript.Run(context.WithoutCancel(ctx), c.rdb, []string{c.key(listLockKey)}, token)
+	}, true, nil
+}
+
+func newLockToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
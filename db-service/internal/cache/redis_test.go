@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	pb "task-api/taskpb/v1"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+const testPrefix = "test:v2:"
+
+func newTestRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	t.Helper()
+	m := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return NewRedis(rdb, "test", time.Hour), m
+}
+
+func task(id string, version int64, tags ...string) *pb.Task {
+	return &pb.Task{ID: id, Header: "h" + id, Body: "b" + id, Version: version, Tags: tags}
+}
+
+func mustPut(t *testing.T, c *Redis, write bool, entries ...Entry) {
+	t.Helper()
+	if err := c.PutTasks(context.Background(), entries, write); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustList(t *testing.T, c *Redis) ([]*pb.Task, bool) {
+	t.Helper()
+	tasks, ok, err := c.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tasks, ok
+}
+
+func ids(tasks []*pb.Task) string {
+	s := make([]string, len(tasks))
+	for i, t := range tasks {
+		s[i] = t.ID
+	}
+	return strings.Join(s, ",")
+}
+
+func TestRedisPutTaskKeepsNewerVersion(t *testing.T) {
+	c, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	mustPut(t, c, true, Entry{Task: task("1", 2, "a", "b")})
+	mustPut(t, c, true, Entry{Task: task("1", 1)})
+	got, ok, err := c.GetTask(ctx, "1")
+	if err != nil || !ok || !proto.Equal(got, task("1", 2, "a", "b")) {
+		t.Fatalf("GetTask = %v, %v, %v, want version 2 with its tags", got, ok, err)
+	}
+
+	// a tombstone hides the task and keeps its version, an older write doesn't bring it back
+	mustPut(t, c, true, Entry{Task: task("1", 3), Deleted: true})
+	mustPut(t, c, true, Entry{Task: task("1", 2)})
+	if got, ok, err := c.GetTask(ctx, "1"); err != nil || ok {
+		t.Fatalf("GetTask of a deleted task = %v, %v, %v, want a miss", got, ok, err)
+	}
+}
+
+func TestRedisPutTaskUpdatesCachedIndex(t *testing.T) {
+	c, m := newTestRedis(t)
+	ctx := context.Background()
+
+	// without an index the task is cached on its own
+	mustPut(t, c, true, Entry{Task: task("5", 1)})
+	if m.Exists(testPrefix + taskIndexKey) {
+		t.Fatal("PutTasks created the index")
+	}
+	if gen, _ := c.Gen(ctx); gen != 1 {
+		t.Fatalf("Gen after a write = %d, want 1", gen)
+	}
+
+	if err := c.SetList(ctx, []*pb.Task{task("2", 1), task("10", 1)}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if tasks, ok := mustList(t, c); !ok || ids(tasks) != "2,10" {
+		t.Fatalf("List = %s, %v, want 2,10", ids(tasks), ok)
+	}
+
+	mustPut(t, c, true, Entry{Task: task("3", 1)}, Entry{Task: task("2", 2), Deleted: true})
+	if tasks, ok := mustList(t, c); !ok || ids(tasks) != "3,10" {
+		t.Fatalf("List after a create and a delete = %s, %v, want 3,10", ids(tasks), ok)
+	}
+
+	// every written task counts, reads don't
+	mustPut(t, c, false, Entry{Task: task("10", 1)})
+	if gen, _ := c.Gen(ctx); gen != 3 {
+		t.Fatalf("Gen = %d, want 3 after three written tasks", gen)
+	}
+}
+
+func TestRedisPutTaskReloadsFlushedScript(t *testing.T) {
+	c, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	mustPut(t, c, true, Entry{Task: task("1", 1)})
+	if err := c.rdb.ScriptFlush(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+	mustPut(t, c, true, Entry{Task: task("1", 2)})
+	if got, ok, err := c.GetTask(ctx, "1"); err != nil || !ok || got.Version != 2 {
+		t.Fatalf("GetTask = %v, %v, %v, want version 2", got, ok, err)
+	}
+}
+
+func TestRedisSetList(t *testing.T) {
+	c, _ := newTestRedis(t)
+	ctx := context.Background()
+
+	// a write after gen was read keeps the list that was read before it out of the cache
+	gen, err := c.Gen(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustPut(t, c, true, Entry{Task: task("2", 1)})
+	if err := c.SetList(ctx, []*pb.Task{task("1", 1)}, gen); err != nil {
+		t.Fatal(err)
+	}
+	if tasks, ok := mustList(t, c); ok {
+		t.Fatalf("List = %s after a stale SetList, want a miss", ids(tasks))
+	}
+
+	gen, _ = c.Gen(ctx)
+	if err := c.SetList(ctx, []*pb.Task{task("1", 1), task("2", 1)}, gen); err != nil {
+		t.Fatal(err)
+	}
+	if tasks, ok := mustList(t, c); !ok || ids(tasks) != "1,2" {
+		t.Fatalf("List = %s, %v, want 1,2", ids(tasks), ok)
+	}
+
+	// an empty list is cached too
+	empty, _ := newTestRedis(t)
+	if err := empty.SetList(ctx, nil, 0); err != nil {
+		t.Fatal(err)
+	}
+	if tasks, ok := mustList(t, empty); !ok || len(tasks) != 0 {
+		t.Fatalf("List = %v, %v, want an empty cached list", tasks, ok)
+	}
+}
+
+func TestRedisKeysUnderPrefix(t *testing.T) {
+	c, m := newTestRedis(t)
+	ctx := context.Background()
+
+	mustPut(t, c, true, Entry{Task: task("1", 1)})
+	if err := c.SetList(ctx, []*pb.Task{task("1", 1)}, 1); err != nil {
+		t.Fatal(err)
+	}
+	unlock, ok, err := c.LockList(ctx)
+	if err != nil || !ok {
+		t.Fatalf("LockList = %v, %v", ok, err)
+	}
+	defer unlock()
+	if err := c.MarkDirty(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{testPrefix + "task:1", testPrefix + dirtyKey, testPrefix + taskGenKey, testPrefix + taskIndexKey, testPrefix + listLockKey}
+	for _, k := range want {
+		if !m.Exists(k) {
+			t.Errorf("key %q is missing", k)
+		}
+	}
+	for _, k := range m.Keys() {
+		if !strings.HasPrefix(k, testPrefix) {
+			t.Errorf("key %q is outside the prefix", k)
+		}
+	}
+}
+
+func TestRedisDeletesUndecodableEntries(t *testing.T) {
+	c, m := newTestRedis(t)
+	ctx := context.Background()
+
+	if err := c.SetList(ctx, []*pb.Task{task("1", 1), task("2", 1)}, 0); err != nil {
+		t.Fatal(err)
+	}
+	m.HSet(testPrefix+"task:2", "version", "x")
+
+	if tasks, ok, err := c.List(ctx); err != nil || ok {
+		t.Fatalf("List with a broken entry = %v, %v, %v, want a miss", tasks, ok, err)
+	}
+	if m.Exists(testPrefix + "task:2") {
+		t.Fatal("List kept the broken entry")
+	}
+	if !m.Exists(testPrefix + "task:1") {
+		t.Fatal("List deleted a valid entry")
+	}
+
+	m.HSet(testPrefix+"task:1", "tags", "not json")
+	if got, ok, err := c.GetTask(ctx, "1"); err != nil || ok {
+		t.Fatalf("GetTask of a broken entry = %v, %v, %v, want a miss", got, ok, err)
+	}
+	if m.Exists(testPrefix + "task:1") {
+		t.Fatal("GetTask kept the broken entry")
+	}
+}
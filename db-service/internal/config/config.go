@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+type Config struct {
+	GRPCAddr string
+	DSN      string
+	// Driver is pq for lib/pq through database/sql or pgx for a pgx pool with prepared statements
+	Driver string
+
+	// connection pool, zero means no limit for pq and the pgxpool default for pgx. MaxIdleConns is ignored by pgx
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// Postgres is pinged every HealthInterval, the service is not ready while the pings fail
+	HealthInterval time.Duration
+	HealthTimeout  time.Duration
+
+	// /metrics and /readyz are served on MetricsAddr
+	MetricsAddr string
+
+	RedisAddr     string
+	RedisPassword string
+
+	// Cache is redis, memory or none. CacheSize limits the tasks of the memory cache
+	Cache     string
+	CacheSize int
+	CacheTTL  time.Duration
+	// Redis cache and idempotency keys start with CacheNamespace
+	CacheNamespace string
+	// the Redis cache is bypassed after CacheBreakerFailures errors in a row and probed again every CacheBreakerCooldown
+	CacheBreakerFailures int
+	CacheBreakerCooldown time.Duration
+
+	MaxBatchSize int
+
+	// deleted tasks older than TrashRetention are purged every PurgeInterval
+	TrashRetention time.Duration
+	PurgeInterval  time.Duration
+
+	// responses of writes with an Idempotency-Key are replayed for IdempotencyTTL
+	IdempotencyTTL time.Duration
+}
+
+func Load() *Config {
+	return &Config{
+		GRPCAddr: getEnv("DB_GRPC_ADDR", ":8081"),
+		DSN:      getEnv("DB_DSN", "host=127.0.0.1 port=5432 user=dude password=pass dbname=tasksdb sslmode=disable"),
+		Driver:   getEnv("DB_DRIVER", "pq"),
+
+		MaxOpenConns:    getInt("DB_MAX_OPEN_CONNS", 25),
+		MaxIdleConns:    getInt("DB_MAX_IDLE_CONNS", 10),
+		ConnMaxLifetime: getDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		ConnMaxIdleTime: getDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+
+		HealthInterval: getDuration("DB_HEALTH_INTERVAL", 5*time.Second),
+		HealthTimeout:  getDuration("DB_HEALTH_TIMEOUT", 2*time.Second),
+
+		MetricsAddr: getEnv("DB_METRICS_ADDR", ":9091"),
+
+		RedisAddr:     getEnv("DB_REDIS_ADDR", "localhost:6379"),
+		RedisPassword: getEnv("DB_REDIS_PASSWORD", "redkaPass"),
+
+		Cache:     getEnv("DB_CACHE", "redis"),
+		CacheSize: getInt("DB_CACHE_SIZE", 10000),
+		CacheTTL:  getDuration("DB_CACHE_TTL", 10*time.Minute),
+
+		CacheNamespace: getEnv("DB_CACHE_NAMESPACE", "db-service"),
+
+		CacheBreakerFailures: getInt("DB_CACHE_BREAKER_FAILURES", 5),
+		CacheBreakerCooldown: getDuration("DB_CACHE_BREAKER_COOLDOWN", 5*time.Second),
+
+		MaxBatchSize: getInt("DB_MAX_BATCH_SIZE", 100),
+
+		TrashRetention: getDuration("DB_TRASH_RETENTION", 30*24*time.Hour),
+		PurgeInterval:  getDuration("DB_PURGE_INTERVAL", time.Hour),
+
+		IdempotencyTTL: getDuration("DB_IDEMPOTENCY_TTL", 24*time.Hour),
+	}
+}
+
+func getEnv(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return def
+}
+
+func getInt(key string, def int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func getDuration(key string, def time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,22 @@
+package deadline
+
+import (
+	"context"
+	"db-service/internal/pkg/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor reports RPCs that failed because api-service canceled them or their deadline passed
+// as CANCELED or DEADLINE_EXCEEDED. The database and cache errors they cause would be INTERNAL otherwise
+func UnaryServerInterceptor(logger *logger.KafkaLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil && ctx.Err() != nil {
+			logger.Logger().Warn().Err(ctx.Err()).Str("method", info.FullMethod).Msg("request canceled")
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return resp, err
+	}
+}
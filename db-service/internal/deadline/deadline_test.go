@@ -0,0 +1,50 @@
+package deadline
+
+import (
+	"context"
+	"db-service/internal/pkg/logger"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor(logger.NewNopLogger())
+	info := &grpc.UnaryServerInfo{FullMethod: "/taskpb.v1.TaskService/List"}
+	dbErr := errors.New("pq: canceling statement due to user request")
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		code codes.Code
+	}{
+		{"canceled", canceled, dbErr, codes.Canceled},
+		{"deadline exceeded", expired, dbErr, codes.DeadlineExceeded},
+		{"other errors pass", context.Background(), status.Error(codes.NotFound, "task 1 not found"), codes.NotFound},
+		{"success after cancel", canceled, nil, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := func(ctx context.Context, req any) (any, error) {
+				return "resp", tt.err
+			}
+			resp, err := interceptor(tt.ctx, nil, info, handler)
+			if status.Code(err) != tt.code {
+				t.Fatalf("err = %v, want %s", err, tt.code)
+			}
+			if err == nil && resp != "resp" {
+				t.Fatalf("resp = %v, want the handler's", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package health
+
+import (
+	"context"
+	"db-service/internal/pkg/logger"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var postgresUp = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "db_service_postgres_up",
+	Help: "1 if the last health check reached Postgres, 0 otherwise.",
+})
+
+func init() {
+	prometheus.MustRegister(postgresUp)
+}
+
+// Checker pings Postgres every interval and sets the gRPC health status of services: SERVING while the pings
+// succeed, NOT_SERVING while they fail
+type Checker struct {
+	ping     func(context.Context) error
+	server   *health.Server
+	services []string
+	interval time.Duration
+	timeout  time.Duration
+	logger   *logger.KafkaLogger
+	ready    atomic.Bool
+}
+
+// NewChecker assumes Postgres is reachable, main pings it before it starts serving. ping is PingContext
+// of sql.DB or Ping of pgxpool.Pool
+func NewChecker(ping func(context.Context) error, server *health.Server, interval, timeout time.Duration, logger *logger.KafkaLogger, services ...string) *Checker {
+	c := &Checker{
+		ping:     ping,
+		server:   server,
+		services: append([]string{""}, services...),
+		interval: interval,
+		timeout:  timeout,
+		logger:   logger,
+	}
+	c.set(true)
+	return c
+}
+
+// Run checks Postgres until ctx is done
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		err := c.ping(pingCtx)
+		cancel()
+
+		switch {
+		case err != nil && c.Ready():
+			c.logger.Logger().Error().Err(err).Msg("Postgres is unreachable, service is not ready")
+			c.set(false)
+		case err == nil && !c.Ready():
+			c.logger.Logger().Info().Msg("Postgres is reachable again, service is ready")
+			c.set(true)
+		}
+	}
+}
+
+func (c *Checker) Ready() bool {
+	return c.ready.Load()
+}
+
+func (c *Checker) set(ready bool) {
+	c.ready.Store(ready)
+
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	postgresUp.Set(0)
+	if ready {
+		status = healthpb.HealthCheckResponse_SERVING
+		postgresUp.Set(1)
+	}
+	for _, s := range c.services {
+		c.server.SetServingStatus(s, status)
+	}
+}
+
+// ReadyHandler answers 200 while the service is ready and 503 otherwise, for probes that don't speak gRPC
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.Ready() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+}
@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"db-service/internal/pkg/logger"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const testService = "taskpb.TaskService"
+
+// fakePing fails while down is set
+type fakePing struct {
+	down  atomic.Bool
+	calls atomic.Int64
+}
+
+func (p *fakePing) ping(ctx context.Context) error {
+	p.calls.Add(1)
+	if p.down.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// waitFor polls cond until it holds or a few seconds are over
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// servingIs reports whether testService has status, the Checker sets it last
+func servingIs(server *health.Server, status healthpb.HealthCheckResponse_ServingStatus) func() bool {
+	return func() bool {
+		resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: testService})
+		return err == nil && resp.Status == status
+	}
+}
+
+func assertReady(t *testing.T, c *Checker, server *health.Server, ready bool) {
+	t.Helper()
+
+	if c.Ready() != ready {
+		t.Fatalf("Ready() = %v, want %v", c.Ready(), ready)
+	}
+
+	wantCode, wantStatus, wantUp := http.StatusOK, healthpb.HealthCheckResponse_SERVING, 1.0
+	if !ready {
+		wantCode, wantStatus, wantUp = http.StatusServiceUnavailable, healthpb.HealthCheckResponse_NOT_SERVING, 0
+	}
+
+	rec := httptest.NewRecorder()
+	c.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != wantCode {
+		t.Fatalf("ReadyHandler = %d, want %d", rec.Code, wantCode)
+	}
+
+	for _, s := range []string{"", testService} {
+		resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: s})
+		if err != nil || resp.Status != wantStatus {
+			t.Fatalf("health status of %q = %v, %v, want %s", s, resp.GetStatus(), err, wantStatus)
+		}
+	}
+
+	if up := testutil.ToFloat64(postgresUp); up != wantUp {
+		t.Fatalf("db_service_postgres_up = %v, want %v", up, wantUp)
+	}
+}
+
+func TestCheckerFollowsPing(t *testing.T) {
+	p := &fakePing{}
+	server := health.NewServer()
+	c := NewChecker(p.ping, server, time.Millisecond, time.Second, logger.NewNopLogger(), testService)
+	assertReady(t, c, server, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx)
+		close(done)
+	}()
+
+	p.down.Store(true)
+	waitFor(t, servingIs(server, healthpb.HealthCheckResponse_NOT_SERVING))
+	assertReady(t, c, server, false)
+
+	p.down.Store(false)
+	waitFor(t, servingIs(server, healthpb.HealthCheckResponse_SERVING))
+	assertReady(t, c, server, true)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run didn't return after its context was canceled")
+	}
+	calls := p.calls.Load()
+	time.Sleep(10 * time.Millisecond)
+	if p.calls.Load() != calls {
+		t.Fatal("Postgres is still pinged after Run returned")
+	}
+}
@@ -0,0 +1,185 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"db-service/internal/pkg/logger"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// MetadataKey is the gRPC metadata api-service forwards the Idempotency-Key header as
+const MetadataKey = "idempotency-key"
+
+// a key stays reserved for pendingTTL while its first request runs,
+// so a crashed replica doesn't block retries for the whole TTL
+const pendingTTL = time.Minute
+
+// record is stored in Redis under the idempotency key. Response is empty while the first request is running
+type record struct {
+	RequestHash string `json:"request_hash"`
+	Done        bool   `json:"done"`
+	Response    []byte `json:"response,omitempty"`
+}
+
+type Store struct {
+	rdb         *redis.Client
+	ttl         time.Duration
+	prefix      string
+	kafkaLogger *logger.KafkaLogger
+}
+
+// NewStore keeps the keys under namespace:idempotency:, services that share Redis need different namespaces
+func NewStore(rdb *redis.Client, namespace string, ttl time.Duration, logger *logger.KafkaLogger) *Store {
+	return &Store{
+		rdb:         rdb,
+		ttl:         ttl,
+		prefix:      namespace + ":idempotency:",
+		kafkaLogger: logger,
+	}
+}
+
+// UnaryServerInterceptor makes methods idempotent for requests that carry an idempotency key: the first response
+// is stored for the TTL and returned again on replay. A reused key with a different request fails with
+// INVALID_ARGUMENT, a replay while the first request is still running fails with ABORTED.
+// Failed requests are not stored and can be retried with the same key. If Redis is down requests run as usual.
+func (s *Store) UnaryServerInterceptor(methods ...string) grpc.UnaryServerInterceptor {
+	idempotent := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		idempotent[m] = true
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		key := keyFrom(ctx)
+		if key == "" || !idempotent[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		hash, err := requestHash(info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+		redisKey := s.prefix + actorFrom(ctx) + ":" + key
+
+		pending, _ := json.Marshal(record{RequestHash: hash})
+		ok, err := s.rdb.SetNX(ctx, redisKey, pending, pendingTTL).Result()
+		if err != nil {
+			s.kafkaLogger.Logger().Warn().Err(err).Str("key", key).Msg("failed to reserve idempotency key in Redis")
+			return handler(ctx, req)
+		}
+		if !ok {
+			return s.replay(ctx, redisKey, hash, info.FullMethod, req)
+		}
+
+		resp, err := handler(ctx, req)
+		// the key must be released or completed even if the request was canceled meanwhile
+		storeCtx := context.WithoutCancel(ctx)
+		if err != nil {
+			if err := s.rdb.Del(storeCtx, redisKey).Err(); err != nil {
+				s.kafkaLogger.Logger().Warn().Err(err).Str("key", key).Msg("failed to release idempotency key in Redis")
+			}
+			return nil, err
+		}
+
+		data, err := proto.Marshal(resp.(proto.Message))
+		if err != nil {
+			return nil, err
+		}
+		done, _ := json.Marshal(record{RequestHash: hash, Done: true, Response: data})
+		if err := s.rdb.Set(storeCtx, redisKey, done, s.ttl).Err(); err != nil {
+			s.kafkaLogger.Logger().Warn().Err(err).Str("key", key).Msg("failed to store idempotent response in Redis")
+		}
+		return resp, nil
+	}
+}
+
+// replay returns the stored response of the first request with the same key
+func (s *Store) replay(ctx context.Context, redisKey, hash, method string, req any) (any, error) {
+	val, err := s.rdb.Get(ctx, redisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// the first request failed or expired in between
+		return nil, status.Errorf(codes.Aborted, "request with this idempotency key is in progress, retry later")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "can't check idempotency key: %v", err)
+	}
+
+	var rec record
+	if err := json.Unmarshal(val, &rec); err != nil {
+		return nil, status.Errorf(codes.Internal, "corrupted idempotency record: %v", err)
+	}
+	if rec.RequestHash != hash {
+		return nil, status.Errorf(codes.InvalidArgument, "idempotency key was already used with a different request")
+	}
+	if !rec.Done {
+		return nil, status.Errorf(codes.Aborted, "request with this idempotency key is in progress, retry later")
+	}
+
+	resp, err := newResponse(method)
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(rec.Response, resp); err != nil {
+		return nil, status.Errorf(codes.Internal, "corrupted idempotent response: %v", err)
+	}
+	s.kafkaLogger.Logger().Info().Str("method", method).Msg("replayed idempotent response")
+	return resp, nil
+}
+
+// newResponse returns an empty output message of a full gRPC method name like /taskpb.v1.TaskService/Create
+func newResponse(method string) (proto.Message, error) {
+	name := protoreflect.FullName(strings.Replace(strings.TrimPrefix(method, "/"), "/", ".", 1))
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unknown method %s", method)
+	}
+	md, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "unknown method %s", method)
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unknown response of %s", method)
+	}
+	return mt.New().Interface(), nil
+}
+
+func keyFrom(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(MetadataKey); len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// keys are scoped per user so two users can't collide on the same key
+func actorFrom(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("x-user-id"); len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+func requestHash(method string, req any) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req.(proto.Message))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(append([]byte(method+"\n"), data...))
+	return hex.EncodeToString(sum[:]), nil
+}
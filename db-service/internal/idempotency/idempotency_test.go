@@ -0,0 +1,168 @@
+package idempotency
+
+import (
+	"context"
+	"db-service/internal/pkg/logger"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	pb "task-api/taskpb/v1"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var batchCreate = &grpc.UnaryServerInfo{FullMethod: pb.TaskService_BatchCreate_FullMethodName}
+
+// fakeHandler counts its calls and answers with the next id, or with err if it is set
+type fakeHandler struct {
+	calls atomic.Int64
+	err   error
+	// release, if set, holds the call until it is closed
+	release chan struct{}
+}
+
+func (h *fakeHandler) handle(ctx context.Context, req any) (any, error) {
+	n := h.calls.Add(1)
+	if h.release != nil {
+		<-h.release
+	}
+	if h.err != nil {
+		return nil, h.err
+	}
+	return &pb.BatchResult{Results: []*pb.BatchItemResult{{ID: strconv.FormatInt(n, 10), Ok: true}}}, nil
+}
+
+func newTestInterceptor(t *testing.T) (grpc.UnaryServerInterceptor, *miniredis.Miniredis) {
+	t.Helper()
+	m := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	s := NewStore(rdb, "test", time.Hour, logger.NewNopLogger())
+	return s.UnaryServerInterceptor(pb.TaskService_BatchCreate_FullMethodName), m
+}
+
+func withKey(user, key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.MD{MetadataKey: {key}, "x-user-id": {user}})
+}
+
+func request(header string) *pb.BatchCreateRequest {
+	return &pb.BatchCreateRequest{Tasks: []*pb.CreateTask{{Header: header}}}
+}
+
+func TestReplayReturnsStoredResponse(t *testing.T) {
+	intercept, m := newTestInterceptor(t)
+	h := &fakeHandler{}
+
+	first, err := intercept(withKey("u1", "k"), request("a"), batchCreate, h.handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := intercept(withKey("u1", "k"), request("a"), batchCreate, h.handle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(first.(proto.Message), replayed.(proto.Message)) || h.calls.Load() != 1 {
+		t.Fatalf("replay = %v after %d calls, want %v from the only call", replayed, h.calls.Load(), first)
+	}
+
+	// keys are scoped per user and live under the namespace
+	if _, err := intercept(withKey("u2", "k"), request("a"), batchCreate, h.handle); err != nil || h.calls.Load() != 2 {
+		t.Fatalf("same key of another user = %v after %d calls, want a second call", err, h.calls.Load())
+	}
+	for _, k := range m.Keys() {
+		if !strings.HasPrefix(k, "test:idempotency:") {
+			t.Fatalf("key %q is outside the namespace", k)
+		}
+	}
+	if ttl := m.TTL("test:idempotency:u1:k"); ttl != time.Hour {
+		t.Fatalf("TTL of the stored response = %s, want 1h", ttl)
+	}
+}
+
+func TestReusedKeyWithOtherRequest(t *testing.T) {
+	intercept, _ := newTestInterceptor(t)
+	h := &fakeHandler{}
+
+	if _, err := intercept(withKey("u1", "k"), request("a"), batchCreate, h.handle); err != nil {
+		t.Fatal(err)
+	}
+	_, err := intercept(withKey("u1", "k"), request("b"), batchCreate, h.handle)
+	if status.Code(err) != codes.InvalidArgument || h.calls.Load() != 1 {
+		t.Fatalf("err = %v after %d calls, want InvalidArgument without a second call", err, h.calls.Load())
+	}
+}
+
+func TestFailedRequestReleasesKey(t *testing.T) {
+	intercept, m := newTestInterceptor(t)
+	h := &fakeHandler{err: errors.New("db is down")}
+
+	if _, err := intercept(withKey("u1", "k"), request("a"), batchCreate, h.handle); err == nil {
+		t.Fatal("the error of the handler was lost")
+	}
+	if m.Exists("test:idempotency:u1:k") {
+		t.Fatal("failed request kept its key")
+	}
+
+	h.err = nil
+	if _, err := intercept(withKey("u1", "k"), request("a"), batchCreate, h.handle); err != nil || h.calls.Load() != 2 {
+		t.Fatalf("retry = %v after %d calls, want it to run", err, h.calls.Load())
+	}
+}
+
+func TestReplayWhileInProgress(t *testing.T) {
+	intercept, _ := newTestInterceptor(t)
+	h := &fakeHandler{release: make(chan struct{})}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := intercept(withKey("u1", "k"), request("a"), batchCreate, h.handle)
+		errs <- err
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for h.calls.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("first request didn't reach the handler")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := intercept(withKey("u1", "k"), request("a"), batchCreate, h.handle)
+	if status.Code(err) != codes.Aborted {
+		t.Fatalf("replay while the first request runs = %v, want Aborted", err)
+	}
+
+	close(h.release)
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if n := h.calls.Load(); n != 1 {
+		t.Fatalf("handler called %d times, want 1", n)
+	}
+}
+
+func TestRunsWithoutKeyOrRedis(t *testing.T) {
+	intercept, m := newTestInterceptor(t)
+	h := &fakeHandler{}
+
+	for range 2 {
+		if _, err := intercept(context.Background(), request("a"), batchCreate, h.handle); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.Close()
+	if _, err := intercept(withKey("u1", "k"), request("a"), batchCreate, h.handle); err != nil {
+		t.Fatalf("with Redis down = %v, want the request to run", err)
+	}
+	if n := h.calls.Load(); n != 3 {
+		t.Fatalf("handler called %d times, want 3", n)
+	}
+}
@@ -32,9 +32,17 @@ func (l *KafkaLogger) Logger() *zerolog.Logger {
 }
 
 func (l *KafkaLogger) Close() error {
+	if l.writer == nil {
+		return nil
+	}
 	return l.writer.Close()
 }
 
+// NewNopLogger discards everything, for tools that run TaskManager without Kafka
+func NewNopLogger() *KafkaLogger {
+	return &KafkaLogger{logger: zerolog.Nop()}
+}
+
 func NewKafkaLogger(serviceName string) *KafkaLogger {
 	writer := kafka.NewWriter(kafka.WriterConfig{
 		Brokers:  []string{"localhost:9092", "localhost:9093", "localhost:9094"},
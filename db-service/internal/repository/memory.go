@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	pb "task-api/taskpb/v1"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Memory keeps tasks in process memory, it is meant for tests and local runs. Transactions run one at a time,
+// fn of InTx must not call the repository itself
+type Memory struct {
+	mu          sync.Mutex
+	tasks       map[string]memoryTask
+	events      []*pb.TaskEvent
+	lastID      int64
+	lastEventID int64
+}
+
+type memoryTask struct {
+	task *pb.Task
+	// zero unless the task is in the trash
+	deletedAt time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		tasks: make(map[string]memoryTask),
+	}
+}
+
+func (m *Memory) InTx(ctx context.Context, fn func(tx Tx) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx := &memoryTx{
+		m:      m,
+		tasks:  make(map[string]memoryTask),
+		lastID: m.lastID,
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	maps.Copy(m.tasks, tx.tasks)
+	for _, e := range tx.events {
+		m.lastEventID++
+		e.ID = strconv.FormatInt(m.lastEventID, 10)
+		m.events = append(m.events, e)
+	}
+	m.lastID = tx.lastID
+	return nil
+}
+
+func (m *Memory) Get(ctx context.Context, id string) (*pb.Task, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.tasks[id]
+	if !ok || !t.deletedAt.IsZero() {
+		return nil, false, nil
+	}
+	return cloneTask(t.task), true, nil
+}
+
+func (m *Memory) List(ctx context.Context) ([]*pb.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var tasks []*pb.Task
+	for _, t := range m.tasks {
+		if t.deletedAt.IsZero() {
+			tasks = append(tasks, cloneTask(t.task))
+		}
+	}
+	slices.SortFunc(tasks, func(a, b *pb.Task) int {
+		return cmp.Compare(idOrder(a.ID), idOrder(b.ID))
+	})
+	return tasks, nil
+}
+
+func (m *Memory) Trash(ctx context.Context) ([]*pb.TrashedTask, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var trashed []memoryTask
+	for _, t := range m.tasks {
+		if !t.deletedAt.IsZero() {
+			trashed = append(trashed, t)
+		}
+	}
+	slices.SortFunc(trashed, func(a, b memoryTask) int {
+		if c := b.deletedAt.Compare(a.deletedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(idOrder(a.task.ID), idOrder(b.task.ID))
+	})
+
+	tasks := make([]*pb.TrashedTask, 0, len(trashed))
+	for _, t := range trashed {
+		tasks = append(tasks, &pb.TrashedTask{Task: cloneTask(t.task), DeletedAt: timestamppb.New(t.deletedAt)})
+	}
+	return tasks, nil
+}
+
+// Search matches tasks that contain every word of query, case-insensitive and without stemming.
+// Matches in the header rank higher than in the body
+func (m *Memory) Search(ctx context.Context, query string, limit, offset int) ([]*pb.SearchHit, int32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	words := strings.Fields(strings.ToLower(query))
+	var hits []*pb.SearchHit
+	for _, t := range m.tasks {
+		if !t.deletedAt.IsZero() || len(words) == 0 {
+			continue
+		}
+		header, body := strings.ToLower(t.task.Header), strings.ToLower(t.task.Body)
+		var rank float32
+		for _, w := range words {
+			h, b := strings.Count(header, w), strings.Count(body, w)
+			if h+b == 0 {
+				rank = 0
+				break
+			}
+			rank += float32(h) + 0.4*float32(b)
+		}
+		if rank == 0 {
+			continue
+		}
+		hits = append(hits, &pb.SearchHit{
+			Task:          cloneTask(t.task),
+			Rank:          rank,
+			HeaderSnippet: highlight(t.task.Header, words),
+			BodySnippet:   highlight(t.task.Body, words),
+		})
+	}
+	slices.SortFunc(hits, func(a, b *pb.SearchHit) int {
+		if c := cmp.Compare(b.Rank, a.Rank); c != 0 {
+			return c
+		}
+		return cmp.Compare(idOrder(a.Task.ID), idOrder(b.Task.ID))
+	})
+
+	total := int32(len(hits))
+	hits = hits[min(offset, len(hits)):]
+	return hits[:min(limit, len(hits))], total, nil
+}
+
+func (m *Memory) History(ctx context.Context, id string) ([]*pb.TaskEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []*pb.TaskEvent
+	for _, e := range m.events {
+		if e.TaskID == id {
+			events = append(events, proto.Clone(e).(*pb.TaskEvent))
+		}
+	}
+	return events, nil
+}
+
+func (m *Memory) Purge(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var n int64
+	for id, t := range m.tasks {
+		if !t.deletedAt.IsZero() && t.deletedAt.Before(before) {
+			delete(m.tasks, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// memoryTx keeps its changes apart until InTx commits them
+type memoryTx struct {
+	m      *Memory
+	tasks  map[string]memoryTask
+	events []*pb.TaskEvent
+	lastID int64
+}
+
+func (tx *memoryTx) get(id string) (memoryTask, bool) {
+	if t, ok := tx.tasks[id]; ok {
+		return t, true
+	}
+	t, ok := tx.m.tasks[id]
+	return t, ok
+}
+
+func (tx *memoryTx) Create(ctx context.Context, tasks []*pb.CreateTask) ([]*pb.Task, error) {
+	created := make([]*pb.Task, 0, len(tasks))
+	for _, task := range tasks {
+		tx.lastID++
+		t := &pb.Task{
+			ID:      strconv.FormatInt(tx.lastID, 10),
+			Header:  task.Header,
+			Body:    task.Body,
+			Tags:    slices.Clone(task.Tags),
+			Version: 1,
+		}
+		tx.tasks[t.ID] = memoryTask{task: t}
+		created = append(created, cloneTask(t))
+	}
+	return created, nil
+}
+
+func (tx *memoryTx) Lock(ctx context.Context, id string, deleted bool) (*pb.Task, bool, error) {
+	t, ok := tx.get(id)
+	if !ok || t.deletedAt.IsZero() == deleted {
+		return nil, false, nil
+	}
+	return cloneTask(t.task), true, nil
+}
+
+func (tx *memoryTx) Change(ctx context.Context, id string, c Change) (*pb.Task, error) {
+	t, ok := tx.get(id)
+	if !ok {
+		return nil, fmt.Errorf("task %s not found", id)
+	}
+
+	changed := memoryTask{task: cloneTask(t.task), deletedAt: t.deletedAt}
+	if c.Header != nil {
+		changed.task.Header = *c.Header
+	}
+	if c.Body != nil {
+		changed.task.Body = *c.Body
+	}
+	if c.IsDone != nil {
+		changed.task.IsDone = *c.IsDone
+	}
+	if c.Deleted != nil {
+		changed.deletedAt = time.Time{}
+		if *c.Deleted {
+			changed.deletedAt = time.Now()
+		}
+	}
+	changed.task.Version++
+
+	tx.tasks[id] = changed
+	return cloneTask(changed.task), nil
+}
+
+func (tx *memoryTx) LockAndChange(ctx context.Context, ids []string, deleted bool, c Change) ([]Changed, error) {
+	return lockAndChange(ctx, tx, ids, deleted, c)
+}
+
+func (tx *memoryTx) AddEvents(ctx context.Context, events []Event) error {
+	for _, e := range events {
+		tx.events = append(tx.events, &pb.TaskEvent{
+			TaskID:    e.TaskID,
+			Actor:     e.Actor,
+			Operation: e.Operation,
+			At:        timestamppb.Now(),
+			Before:    cloneTask(e.Before),
+			After:     cloneTask(e.After),
+		})
+	}
+	return nil
+}
+
+func cloneTask(t *pb.Task) *pb.Task {
+	if t == nil {
+		return nil
+	}
+	return proto.Clone(t).(*pb.Task)
+}
+
+// highlight wraps the words in s like ts_headline does
+func highlight(s string, words []string) string {
+	lower := strings.ToLower(s)
+	if len(lower) != len(s) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		matched := false
+		for _, w := range words {
+			if strings.HasPrefix(lower[i:], w) {
+				b.WriteString("<b>" + s[i:i+len(w)] + "</b>")
+				i += len(w)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+func idOrder(id string) int64 {
+	n, _ := strconv.ParseInt(id, 10, 64)
+	return n
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	pb "task-api/taskpb/v1"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// statements are prepared on every connection of the pool, queries use their names instead of the SQL
+var statements = map[string]string{
+	"get":           getQuery,
+	"list":          listQuery,
+	"trash":         trashQuery,
+	"search":        searchQuery,
+	"search_count":  searchCountQuery,
+	"create":        createQuery,
+	"lock":          lockQuery,
+	"change":        changeQuery,
+	"change_locked": changeLockedQuery,
+	"add_event":     addEventQuery,
+	"history":       historyQuery,
+	"purge":         purgeQuery,
+}
+
+// Pgx keeps tasks in the same tables as Postgres, it talks to them with pgx instead of lib/pq. Statements are
+// prepared once per connection and bulk writes go to the server in one round trip with pgx.Batch
+type Pgx struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgx(pool *pgxpool.Pool) *Pgx {
+	return &Pgx{pool: pool}
+}
+
+// NewPgxPool connects to dsn and prepares statements on every new connection. maxConns of zero keeps
+// the pgxpool default
+func NewPgxPool(ctx context.Context, dsn string, maxConns int, maxLifetime, maxIdleTime time.Duration) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse dsn error %s", err)
+	}
+	if maxConns > 0 {
+		cfg.MaxConns = int32(maxConns)
+	}
+	cfg.MaxConnLifetime = maxLifetime
+	cfg.MaxConnIdleTime = maxIdleTime
+	cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+		for name, sql := range statements {
+			if _, err := conn.Prepare(ctx, name, sql); err != nil {
+				return fmt.Errorf("prepare %s error %s", name, err)
+			}
+		}
+		return nil
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connect error %s", err)
+	}
+	return pool, nil
+}
+
+func (p *Pgx) InTx(ctx context.Context, fn func(tx Tx) error) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx error %s", err)
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+
+	if err := fn(pgxTx{tx: tx}); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit error %s", err)
+	}
+	return nil
+}
+
+func (p *Pgx) Get(ctx context.Context, id string) (*pb.Task, bool, error) {
+	var t pb.Task
+	err := scanPgxTask(p.pool.QueryRow(ctx, "get", id), &t)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("select error %s", err)
+	}
+	return &t, true, nil
+}
+
+func (p *Pgx) List(ctx context.Context) ([]*pb.Task, error) {
+	rows, err := p.pool.Query(ctx, "list")
+	if err != nil {
+		return nil, fmt.Errorf("select from tasks error %s", err)
+	}
+	tasks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*pb.Task, error) {
+		var t pb.Task
+		return &t, scanPgxTask(row, &t)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("select from tasks rows error %s", err)
+	}
+	return tasks, nil
+}
+
+func (p *Pgx) Trash(ctx context.Context) ([]*pb.TrashedTask, error) {
+	rows, err := p.pool.Query(ctx, "trash")
+	if err != nil {
+		return nil, fmt.Errorf("select trash error %s", err)
+	}
+	tasks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*pb.TrashedTask, error) {
+		var (
+			t         pb.Task
+			deletedAt time.Time
+		)
+		err := row.Scan(&t.ID, &t.Header, &t.Body, &t.IsDone, &t.Version, &t.Tags, &deletedAt)
+		return &pb.TrashedTask{Task: &t, DeletedAt: timestamppb.New(deletedAt)}, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("select trash rows error %s", err)
+	}
+	return tasks, nil
+}
+
+func (p *Pgx) Search(ctx context.Context, query string, limit, offset int) ([]*pb.SearchHit, int32, error) {
+	rows, err := p.pool.Query(ctx, "search", query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("search error %s", err)
+	}
+	var total int32
+	hits, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*pb.SearchHit, error) {
+		var (
+			t   pb.Task
+			hit = &pb.SearchHit{Task: &t}
+		)
+		err := row.Scan(&t.ID, &t.Header, &t.Body, &t.IsDone, &t.Version, &t.Tags, &hit.Rank, &hit.HeaderSnippet, &hit.BodySnippet, &total)
+		return hit, err
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("search rows error %s", err)
+	}
+	if len(hits) == 0 && offset > 0 {
+		if err := p.pool.QueryRow(ctx, "search_count", query).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("search count error %s", err)
+		}
+	}
+	return hits, total, nil
+}
+
+func (p *Pgx) History(ctx context.Context, id string) ([]*pb.TaskEvent, error) {
+	rows, err := p.pool.Query(ctx, "history", id)
+	if err != nil {
+		return nil, fmt.Errorf("select history error %s", err)
+	}
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*pb.TaskEvent, error) {
+		var (
+			e             pb.TaskEvent
+			before, after []byte
+			at            time.Time
+		)
+		if err := row.Scan(&e.ID, &e.TaskID, &e.Actor, &e.Operation, &before, &after, &at); err != nil {
+			return nil, err
+		}
+		e.At = timestamppb.New(at)
+		var err error
+		if e.Before, err = unmarshalTask(before); err != nil {
+			return nil, err
+		}
+		if e.After, err = unmarshalTask(after); err != nil {
+			return nil, err
+		}
+		return &e, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("select history rows error %s", err)
+	}
+	return events, nil
+}
+
+func (p *Pgx) Purge(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := p.pool.Exec(ctx, "purge", before)
+	if err != nil {
+		return 0, fmt.Errorf("purge error %s", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// scanPgxTask reads the columns of getQuery, tags are scanned from text[] as they are
+func scanPgxTask(row pgx.Row, t *pb.Task) error {
+	return row.Scan(&t.ID, &t.Header, &t.Body, &t.IsDone, &t.Version, &t.Tags)
+}
+
+type pgxTx struct {
+	tx pgx.Tx
+}
+
+// Create sends all inserts in one batch
+func (t pgxTx) Create(ctx context.Context, tasks []*pb.CreateTask) ([]*pb.Task, error) {
+	batch := &pgx.Batch{}
+	for _, task := range tasks {
+		batch.Queue("create", task.Header, task.Body, task.Tags)
+	}
+	results := t.tx.SendBatch(ctx, batch)
+	defer results.Close()
+
+	created := make([]*pb.Task, 0, len(tasks))
+	for range tasks {
+		var c pb.Task
+		if err := scanPgxTask(results.QueryRow(), &c); err != nil {
+			return nil, fmt.Errorf("insert into tasks insert error %s", err)
+		}
+		created = append(created, &c)
+	}
+	return created, results.Close()
+}
+
+func (t pgxTx) Lock(ctx context.Context, id string, deleted bool) (*pb.Task, bool, error) {
+	var task pb.Task
+	err := scanPgxTask(t.tx.QueryRow(ctx, "lock", id, deleted), &task)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("select for update error %s", err)
+	}
+	return &task, true, nil
+}
+
+func (t pgxTx) Change(ctx context.Context, id string, c Change) (*pb.Task, error) {
+	var task pb.Task
+	if err := scanPgxTask(t.tx.QueryRow(ctx, "change", id, c.Header, c.Body, c.IsDone, c.Deleted), &task); err != nil {
+		return nil, fmt.Errorf("update error %s", err)
+	}
+	return &task, nil
+}
+
+// LockAndChange sends the lock and the change of every id in one batch. The change has the condition of the lock,
+// so for an id the lock didn't find both return no row
+func (t pgxTx) LockAndChange(ctx context.Context, ids []string, deleted bool, c Change) ([]Changed, error) {
+	batch := &pgx.Batch{}
+	for _, id := range ids {
+		batch.Queue("lock", id, deleted)
+		batch.Queue("change_locked", id, c.Header, c.Body, c.IsDone, c.Deleted, deleted)
+	}
+	results := t.tx.SendBatch(ctx, batch)
+	defer results.Close()
+
+	changed := make([]Changed, len(ids))
+	for i := range ids {
+		var before, after pb.Task
+		lockErr := scanPgxTask(results.QueryRow(), &before)
+		changeErr := scanPgxTask(results.QueryRow(), &after)
+		if errors.Is(lockErr, pgx.ErrNoRows) && errors.Is(changeErr, pgx.ErrNoRows) {
+			continue
+		}
+		if lockErr != nil {
+			return nil, fmt.Errorf("select for update error %s", lockErr)
+		}
+		if changeErr != nil {
+			return nil, fmt.Errorf("update error %s", changeErr)
+		}
+		changed[i] = Changed{Before: &before, After: &after}
+	}
+	return changed, results.Close()
+}
+
+// AddEvents sends all inserts in one batch, before and after go to jsonb as they are
+func (t pgxTx) AddEvents(ctx context.Context, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, e := range events {
+		before, err := taskJSON(e.Before)
+		if err != nil {
+			return err
+		}
+		after, err := taskJSON(e.After)
+		if err != nil {
+			return err
+		}
+		batch.Queue("add_event", e.TaskID, e.Actor, e.Operation, before, after)
+	}
+
+	if err := t.tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("insert into task_events error %s", err)
+	}
+	return nil
+}
+
+// taskJSON returns nil for a nil task so that it is stored as NULL
+func taskJSON(t *pb.Task) (any, error) {
+	if t == nil {
+		return nil, nil
+	}
+	data, err := protojson.Marshal(t)
+	if err != nil {
+		return nil, fmt.Errorf("can't marshal task, %v", err)
+	}
+	return data, nil
+}
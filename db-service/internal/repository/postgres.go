@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	pb "task-api/taskpb/v1"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/lib/pq"
+)
+
+const (
+	getQuery   = "SELECT id, header, body, isdone, version, tags FROM tasks WHERE id = $1 AND deleted_at IS NULL;"
+	listQuery  = "SELECT id, header, body, isdone, version, tags FROM tasks WHERE deleted_at IS NULL ORDER BY id"
+	trashQuery = "SELECT id, header, body, isdone, version, tags, deleted_at FROM tasks WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id"
+	// a nil slice is sent as NULL by both drivers
+	createQuery = "INSERT INTO tasks(header, body, tags) VALUES ($1, $2, COALESCE($3::text[], '{}')) RETURNING id, header, body, isdone, version, tags"
+	lockQuery   = "SELECT id, header, body, isdone, version, tags FROM tasks WHERE id = $1 AND (deleted_at IS NOT NULL) = $2 FOR UPDATE;"
+	// $5 is NULL to keep deleted_at, true to move the task to the trash and false to restore it
+	changeSet = `UPDATE tasks SET header = COALESCE($2, header), body = COALESCE($3, body), isdone = COALESCE($4, isdone),
+	deleted_at = CASE WHEN $5::boolean IS NULL THEN deleted_at WHEN $5 THEN now() ELSE NULL END,
+	version = version + 1`
+	changeQuery = changeSet + `
+WHERE id = $1 RETURNING id, header, body, isdone, version, tags;`
+	// changeLockedQuery is queued right after lockQuery with the same $6, it skips the tasks the lock didn't find
+	changeLockedQuery = changeSet + `
+WHERE id = $1 AND (deleted_at IS NOT NULL) = $6 RETURNING id, header, body, isdone, version, tags;`
+	addEventQuery = "INSERT INTO task_events(task_id, actor, operation, before, after) VALUES ($1, $2, $3, $4, $5);"
+	historyQuery  = "SELECT id, task_id, actor, operation, before, after, created_at FROM task_events WHERE task_id = $1 ORDER BY id;"
+	purgeQuery    = "DELETE FROM tasks WHERE deleted_at < $1;"
+)
+
+// tasks_ru_en is created in _postgres/init.sql, search_vector is built with the same configuration
+const searchQuery = `
+SELECT id, header, body, isdone, version, tags,
+	ts_rank_cd(search_vector, q) AS rank,
+	ts_headline('tasks_ru_en', header, q, 'StartSel=<b>, StopSel=</b>, HighlightAll=true'),
+	ts_headline('tasks_ru_en', body, q, 'StartSel=<b>, StopSel=</b>, MaxFragments=2, MaxWords=20, MinWords=5'),
+	count(*) OVER () AS total
+FROM tasks, websearch_to_tsquery('tasks_ru_en', $1) AS q
+WHERE search_vector @@ q AND deleted_at IS NULL
+ORDER BY rank DESC, id
+LIMIT $2 OFFSET $3;`
+
+// searchCountQuery counts the matches of searchQuery, its total has no row to come with on a page past the end
+const searchCountQuery = `
+SELECT count(*)
+FROM tasks, websearch_to_tsquery('tasks_ru_en', $1) AS q
+WHERE search_vector @@ q AND deleted_at IS NULL;`
+
+// Postgres keeps tasks in the tables created by _postgres/init.sql
+type Postgres struct {
+	db *sql.DB
+}
+
+func NewPostgres(db *sql.DB) *Postgres {
+	return &Postgres{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask reads the columns of getQuery, lib/pq needs pq.Array for the tags
+func scanTask(row rowScanner, t *pb.Task) error {
+	return row.Scan(&t.ID, &t.Header, &t.Body, &t.IsDone, &t.Version, pq.Array(&t.Tags))
+}
+
+func (p *Postgres) InTx(ctx context.Context, fn func(tx Tx) error) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx error %s", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(postgresTx{tx: tx}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit error %s", err)
+	}
+	return nil
+}
+
+func (p *Postgres) Get(ctx context.Context, id string) (*pb.Task, bool, error) {
+	var t pb.Task
+	err := scanTask(p.db.QueryRowContext(ctx, getQuery, id), &t)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("select error %s", err)
+	}
+	return &t, true, nil
+}
+
+func (p *Postgres) List(ctx context.Context) ([]*pb.Task, error) {
+	rows, err := p.db.QueryContext(ctx, listQuery)
+	if err != nil {
+		return nil, fmt.Errorf("select from tasks error %s", err)
+	}
+	defer rows.Close()
+
+	var tasks []*pb.Task
+	for rows.Next() {
+		var t pb.Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, fmt.Errorf("rows scan error %s", err)
+		}
+		tasks = append(tasks, &t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select from tasks rows error %s", err)
+	}
+	return tasks, nil
+}
+
+func (p *Postgres) Trash(ctx context.Context) ([]*pb.TrashedTask, error) {
+	rows, err := p.db.QueryContext(ctx, trashQuery)
+	if err != nil {
+		return nil, fmt.Errorf("select trash error %s", err)
+	}
+	defer rows.Close()
+
+	var tasks []*pb.TrashedTask
+	for rows.Next() {
+		var (
+			t         pb.Task
+			deletedAt time.Time
+		)
+		if err := rows.Scan(&t.ID, &t.Header, &t.Body, &t.IsDone, &t.Version, pq.Array(&t.Tags), &deletedAt); err != nil {
+			return nil, fmt.Errorf("rows scan error %s", err)
+		}
+		tasks = append(tasks, &pb.TrashedTask{Task: &t, DeletedAt: timestamppb.New(deletedAt)})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select trash rows error %s", err)
+	}
+	return tasks, nil
+}
+
+func (p *Postgres) Search(ctx context.Context, query string, limit, offset int) ([]*pb.SearchHit, int32, error) {
+	rows, err := p.db.QueryContext(ctx, searchQuery, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("search error %s", err)
+	}
+	defer rows.Close()
+
+	var (
+		hits  []*pb.SearchHit
+		total int32
+	)
+	for rows.Next() {
+		var (
+			t   pb.Task
+			hit = &pb.SearchHit{Task: &t}
+		)
+		if err := rows.Scan(&t.ID, &t.Header, &t.Body, &t.IsDone, &t.Version, pq.Array(&t.Tags), &hit.Rank, &hit.HeaderSnippet, &hit.BodySnippet, &total); err != nil {
+			return nil, 0, fmt.Errorf("rows scan error %s", err)
+		}
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("search rows error %s", err)
+	}
+	if len(hits) == 0 && offset > 0 {
+		if err := p.db.QueryRowContext(ctx, searchCountQuery, query).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("search count error %s", err)
+		}
+	}
+	return hits, total, nil
+}
+
+func (p *Postgres) History(ctx context.Context, id string) ([]*pb.TaskEvent, error) {
+	rows, err := p.db.QueryContext(ctx, historyQuery, id)
+	if err != nil {
+		return nil, fmt.Errorf("select history error %s", err)
+	}
+	defer rows.Close()
+
+	var events []*pb.TaskEvent
+	for rows.Next() {
+		var (
+			e             pb.TaskEvent
+			before, after []byte
+			at            time.Time
+		)
+		if err := rows.Scan(&e.ID, &e.TaskID, &e.Actor, &e.Operation, &before, &after, &at); err != nil {
+			return nil, fmt.Errorf("rows scan error %s", err)
+		}
+		e.At = timestamppb.New(at)
+		if e.Before, err = unmarshalTask(before); err != nil {
+			return nil, err
+		}
+		if e.After, err = unmarshalTask(after); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select history rows error %s", err)
+	}
+	return events, nil
+}
+
+func (p *Postgres) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := p.db.ExecContext(ctx, purgeQuery, before)
+	if err != nil {
+		return 0, fmt.Errorf("purge error %s", err)
+	}
+	return res.RowsAffected()
+}
+
+type postgresTx struct {
+	tx *sql.Tx
+}
+
+func (t postgresTx) Create(ctx context.Context, tasks []*pb.CreateTask) ([]*pb.Task, error) {
+	created := make([]*pb.Task, 0, len(tasks))
+	for _, task := range tasks {
+		var c pb.Task
+		if err := scanTask(t.tx.QueryRowContext(ctx, createQuery, task.Header, task.Body, pq.Array(task.Tags)), &c); err != nil {
+			return nil, fmt.Errorf("insert into tasks insert error %s", err)
+		}
+		created = append(created, &c)
+	}
+	return created, nil
+}
+
+func (t postgresTx) Lock(ctx context.Context, id string, deleted bool) (*pb.Task, bool, error) {
+	var task pb.Task
+	err := scanTask(t.tx.QueryRowContext(ctx, lockQuery, id, deleted), &task)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("select for update error %s", err)
+	}
+	return &task, true, nil
+}
+
+func (t postgresTx) Change(ctx context.Context, id string, c Change) (*pb.Task, error) {
+	var task pb.Task
+	if err := scanTask(t.tx.QueryRowContext(ctx, changeQuery, id, c.Header, c.Body, c.IsDone, c.Deleted), &task); err != nil {
+		return nil, fmt.Errorf("update error %s", err)
+	}
+	return &task, nil
+}
+
+func (t postgresTx) LockAndChange(ctx context.Context, ids []string, deleted bool, c Change) ([]Changed, error) {
+	return lockAndChange(ctx, t, ids, deleted, c)
+}
+
+func (t postgresTx) AddEvents(ctx context.Context, events []Event) error {
+	for _, e := range events {
+		before, err := marshalTask(e.Before)
+		if err != nil {
+			return err
+		}
+		after, err := marshalTask(e.After)
+		if err != nil {
+			return err
+		}
+
+		if _, err := t.tx.ExecContext(ctx, addEventQuery, e.TaskID, e.Actor, e.Operation, before, after); err != nil {
+			return fmt.Errorf("insert into task_events error %s", err)
+		}
+	}
+	return nil
+}
+
+// marshalTask returns t as a jsonb parameter, lib/pq would send []byte as bytea
+func marshalTask(t *pb.Task) (sql.NullString, error) {
+	if t == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := protojson.Marshal(t)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("can't marshal task, %v", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func unmarshalTask(data []byte) (*pb.Task, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var t pb.Task
+	if err := protojson.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("can't unmarshal task, %v", err)
+	}
+	return &t, nil
+}
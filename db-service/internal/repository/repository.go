@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	pb "task-api/taskpb/v1"
+	"time"
+)
+
+// TaskRepository stores tasks and their history. Deleted tasks stay in the trash until they are purged
+type TaskRepository interface {
+	// InTx runs fn in one transaction, the changes made through tx are kept only if fn returns nil
+	InTx(ctx context.Context, fn func(tx Tx) error) error
+	// Get returns a task that is not deleted, ok is false if there is no such task
+	Get(ctx context.Context, id string) (task *pb.Task, ok bool, err error)
+	// List returns the tasks that are not deleted ordered by id
+	List(ctx context.Context) ([]*pb.Task, error)
+	// Trash returns the deleted tasks, the most recently deleted first
+	Trash(ctx context.Context) ([]*pb.TrashedTask, error)
+	// Search returns limit tasks matching query starting at offset, ordered by rank, and the number of all matches
+	Search(ctx context.Context, query string, limit, offset int) (hits []*pb.SearchHit, total int32, err error)
+	// History returns the events of a task in the order they happened
+	History(ctx context.Context, id string) ([]*pb.TaskEvent, error)
+	// Purge removes tasks deleted before the given time
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Tx changes tasks inside TaskRepository.InTx
+type Tx interface {
+	// Create inserts tasks and returns them in the same order
+	Create(ctx context.Context, tasks []*pb.CreateTask) ([]*pb.Task, error)
+	// Lock returns a task and keeps others from changing it until the transaction ends. deleted selects
+	// a task from the trash instead, ok is false if there is no such task
+	Lock(ctx context.Context, id string, deleted bool) (task *pb.Task, ok bool, err error)
+	// Change sets the fields of c that are not nil and increments the version of the task
+	Change(ctx context.Context, id string, c Change) (*pb.Task, error)
+	// LockAndChange locks every task like Lock and applies c to the ones it found. The results are in the order
+	// of ids, both tasks are nil for an id that matches no task
+	LockAndChange(ctx context.Context, ids []string, deleted bool, c Change) ([]Changed, error)
+	AddEvents(ctx context.Context, events []Event) error
+}
+
+// Change of a task, nil fields stay as they are. Deleted moves the task to the trash or restores it
+type Change struct {
+	Header  *string
+	Body    *string
+	IsDone  *bool
+	Deleted *bool
+}
+
+// Changed is a task before and after a change
+type Changed struct {
+	Before *pb.Task
+	After  *pb.Task
+}
+
+// lockAndChange runs Lock and Change for every id, it is LockAndChange for the transactions that can't batch them
+func lockAndChange(ctx context.Context, tx Tx, ids []string, deleted bool, c Change) ([]Changed, error) {
+	changed := make([]Changed, len(ids))
+	for i, id := range ids {
+		before, ok, err := tx.Lock(ctx, id, deleted)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		after, err := tx.Change(ctx, id, c)
+		if err != nil {
+			return nil, err
+		}
+		changed[i] = Changed{Before: before, After: after}
+	}
+	return changed, nil
+}
+
+// Event is a change recorded in the history of a task. Before is nil for created tasks
+type Event struct {
+	TaskID    string
+	Actor     string
+	Operation string
+	Before    *pb.Task
+	After     *pb.Task
+}
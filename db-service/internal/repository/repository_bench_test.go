@@ -0,0 +1,147 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"db-service/internal/repository"
+	"os"
+	"strconv"
+	pb "task-api/taskpb/v1"
+	"testing"
+	"time"
+)
+
+const (
+	benchActor = "repobench"
+	benchBatch = 100
+)
+
+// benchRepos connects both repositories to the Postgres of DB_DSN, the tests are skipped without it.
+// The tasks created by the tests are removed when they end
+func benchRepos(b testing.TB) []struct {
+	name string
+	repo repository.TaskRepository
+} {
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		b.Skip("DB_DSN is not set")
+	}
+	ctx := context.Background()
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+	if err := db.PingContext(ctx); err != nil {
+		b.Skipf("postgres is unavailable: %v", err)
+	}
+
+	pool, err := repository.NewPgxPool(ctx, dsn, 0, time.Hour, time.Minute)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(pool.Close)
+
+	b.Cleanup(func() {
+		if _, err := db.ExecContext(ctx, "DELETE FROM task_events WHERE actor = $1;", benchActor); err != nil {
+			b.Errorf("delete bench history: %v", err)
+		}
+		if _, err := db.ExecContext(ctx, "DELETE FROM tasks WHERE header = $1;", benchActor); err != nil {
+			b.Errorf("delete bench tasks: %v", err)
+		}
+	})
+
+	return []struct {
+		name string
+		repo repository.TaskRepository
+	}{
+		{"repo=pq", repository.NewPostgres(db)},
+		{"repo=pgx", repository.NewPgx(pool)},
+	}
+}
+
+// create inserts benchBatch tasks with their events in one transaction
+func create(b *testing.B, repo repository.TaskRepository) []*pb.Task {
+	ctx := context.Background()
+	drafts := make([]*pb.CreateTask, benchBatch)
+	for i := range drafts {
+		drafts[i] = &pb.CreateTask{Header: benchActor, Body: "task " + strconv.Itoa(i), Tags: []string{"bench", strconv.Itoa(i % 10)}}
+	}
+
+	var created []*pb.Task
+	err := repo.InTx(ctx, func(tx repository.Tx) error {
+		var err error
+		if created, err = tx.Create(ctx, drafts); err != nil {
+			return err
+		}
+		events := make([]repository.Event, len(created))
+		for i, t := range created {
+			events[i] = repository.Event{TaskID: t.ID, Actor: benchActor, Operation: "create", After: t}
+		}
+		return tx.AddEvents(ctx, events)
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return created
+}
+
+// BenchmarkRepository runs the same operations through lib/pq and pgx. create and done handle benchBatch tasks
+// per op in one transaction, pgx sends each of them in one batch
+func BenchmarkRepository(b *testing.B) {
+	ctx := context.Background()
+	done := true
+
+	for _, r := range benchRepos(b) {
+		b.Run(r.name+"/op=create", func(b *testing.B) {
+			for b.Loop() {
+				create(b, r.repo)
+			}
+		})
+
+		b.Run(r.name+"/op=get", func(b *testing.B) {
+			tasks := create(b, r.repo)
+			i := 0
+			for b.Loop() {
+				if _, ok, err := r.repo.Get(ctx, tasks[i%len(tasks)].ID); err != nil || !ok {
+					b.Fatalf("Get = %v, %v", ok, err)
+				}
+				i++
+			}
+		})
+
+		b.Run(r.name+"/op=list", func(b *testing.B) {
+			create(b, r.repo)
+			for b.Loop() {
+				if _, err := r.repo.List(ctx); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(r.name+"/op=done", func(b *testing.B) {
+			tasks := create(b, r.repo)
+			ids := make([]string, len(tasks))
+			for i, t := range tasks {
+				ids[i] = t.ID
+			}
+			for b.Loop() {
+				err := r.repo.InTx(ctx, func(tx repository.Tx) error {
+					changed, err := tx.LockAndChange(ctx, ids, false, repository.Change{IsDone: &done})
+					if err != nil {
+						return err
+					}
+					events := make([]repository.Event, len(changed))
+					for i, c := range changed {
+						events[i] = repository.Event{TaskID: ids[i], Actor: benchActor, Operation: "done", Before: c.Before, After: c.After}
+					}
+					return tx.AddEvents(ctx, events)
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
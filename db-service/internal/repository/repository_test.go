@@ -0,0 +1,46 @@
+package repository_test
+
+import (
+	"context"
+	"db-service/internal/repository"
+	pb "task-api/taskpb/v1"
+	"testing"
+)
+
+// testSearchTotal checks that total counts all matches on every page, also on the pages past the last one
+func testSearchTotal(t *testing.T, repo repository.TaskRepository) {
+	ctx := context.Background()
+	drafts := []*pb.CreateTask{
+		{Header: benchActor, Body: "quokka one"},
+		{Header: benchActor, Body: "quokka two"},
+		{Header: benchActor, Body: "quokka three"},
+	}
+	err := repo.InTx(ctx, func(tx repository.Tx) error {
+		_, err := tx.Create(ctx, drafts)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, page := range []struct{ limit, offset, hits int }{{2, 0, 2}, {2, 2, 1}, {2, 4, 0}, {2, 100, 0}} {
+		hits, total, err := repo.Search(ctx, "quokka", page.limit, page.offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hits) != page.hits || total != 3 {
+			t.Errorf("Search at offset %d = %d hits of %d, want %d of 3", page.offset, len(hits), total, page.hits)
+		}
+	}
+}
+
+func TestSearchTotal(t *testing.T) {
+	t.Run("repo=memory", func(t *testing.T) {
+		testSearchTotal(t, repository.NewMemory())
+	})
+	for _, r := range benchRepos(t) {
+		t.Run(r.name, func(t *testing.T) {
+			testSearchTotal(t, r.repo)
+		})
+	}
+}
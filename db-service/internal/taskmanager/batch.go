@@ -0,0 +1,165 @@
+package taskmanager
+
+import (
+	"context"
+	"db-service/internal/cache"
+	"db-service/internal/repository"
+	"fmt"
+	"strconv"
+	pb "task-api/taskpb/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validID reports if id can match a task. Postgres fails a query with a malformed id or one out of the range of
+// the integer id column instead of finding no task, and aborts the transaction with it
+func validID(id string) bool {
+	_, err := strconv.ParseInt(id, 10, 32)
+	return err == nil
+}
+
+func (tm *TaskManager) checkBatchSize(n int) error {
+	if n == 0 {
+		return status.Errorf(codes.InvalidArgument, "batch is empty")
+	}
+	if n > tm.maxBatchSize {
+		return status.Errorf(codes.InvalidArgument, "batch of %d items exceeds the limit of %d", n, tm.maxBatchSize)
+	}
+	return nil
+}
+
+// inTx runs fn in one transaction and writes the changed tasks through to the cache after a successful commit
+func (tm *TaskManager) inTx(ctx context.Context, fn func(tx *taskTx) error) error {
+	var changes []cache.Entry
+	err := tm.repo.InTx(ctx, func(repoTx repository.Tx) error {
+		tx := &taskTx{Tx: repoTx}
+		if err := fn(tx); err != nil {
+			return err
+		}
+		if err := tx.AddEvents(ctx, tx.events); err != nil {
+			tm.kafkaLogger.Logger().Error().Err(err).Int("count", len(tx.events)).Msg("insert into task_events error")
+			return err
+		}
+		changes = tx.changes
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// the transaction is committed, a canceled request must not keep the cache from seeing it
+	if err := tm.cache.PutTasks(context.WithoutCancel(ctx), changes, true); err != nil {
+		tm.kafkaLogger.Logger().Warn().Err(err).Msg("failed to write tasks to cache")
+	} else {
+		tm.kafkaLogger.Logger().Info().Int("count", len(changes)).Msg("wrote tasks to cache")
+	}
+	return nil
+}
+
+func (tm *TaskManager) BatchCreate(ctx context.Context, in *pb.BatchCreateRequest) (*pb.BatchResult, error) {
+	tm.kafkaLogger.Logger().Info().Int("count", len(in.Tasks)).Msg("received BatchCreate request")
+
+	if err := tm.checkBatchSize(len(in.Tasks)); err != nil {
+		return nil, err
+	}
+
+	results := make([]*pb.BatchItemResult, len(in.Tasks))
+	var (
+		valid   []*pb.CreateTask
+		indexes []int
+	)
+	for i, t := range in.Tasks {
+		results[i] = &pb.BatchItemResult{Index: int32(i)}
+		if t.Header == "" && t.Body == "" {
+			results[i].Error = "header and body are empty"
+			continue
+		}
+		valid = append(valid, t)
+		indexes = append(indexes, i)
+	}
+
+	err := tm.inTx(ctx, func(tx *taskTx) error {
+		if len(valid) == 0 {
+			return nil
+		}
+		created, err := tx.Create(ctx, valid)
+		if err != nil {
+			tm.kafkaLogger.Logger().Error().Err(err).Msg("batch insert into tasks error")
+			return err
+		}
+		for j, t := range created {
+			tm.recordEvent(ctx, tx, opCreate, t.ID, nil, t)
+			results[indexes[j]].ID = t.ID
+			results[indexes[j]].Ok = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tm.kafkaLogger.Logger().Info().Int("count", len(in.Tasks)).Msg("batch successfully inserted into DB")
+	return &pb.BatchResult{Results: results}, nil
+}
+
+func (tm *TaskManager) BatchDone(ctx context.Context, in *pb.BatchTaskIDs) (*pb.BatchResult, error) {
+	tm.kafkaLogger.Logger().Info().Int("count", len(in.IDs)).Msg("received BatchDone request")
+	return tm.batchByID(ctx, in.IDs, opDone, doneChange)
+}
+
+func (tm *TaskManager) BatchDelete(ctx context.Context, in *pb.BatchTaskIDs) (*pb.BatchResult, error) {
+	tm.kafkaLogger.Logger().Info().Int("count", len(in.IDs)).Msg("received BatchDelete request")
+	return tm.batchByID(ctx, in.IDs, opDelete, deleteChange)
+}
+
+// batchByID locks and changes all ids with one LockAndChange, ids that match no task are reported as not found
+func (tm *TaskManager) batchByID(ctx context.Context, ids []string, op string, c repository.Change) (*pb.BatchResult, error) {
+	if err := tm.checkBatchSize(len(ids)); err != nil {
+		return nil, err
+	}
+
+	results := make([]*pb.BatchItemResult, len(ids))
+	var (
+		valid   []string
+		indexes []int
+	)
+	for i, id := range ids {
+		results[i] = &pb.BatchItemResult{Index: int32(i), ID: id}
+		if id == "" {
+			results[i].Error = "id is empty"
+			continue
+		}
+		if !validID(id) {
+			results[i].Error = "task not found"
+			continue
+		}
+		valid = append(valid, id)
+		indexes = append(indexes, i)
+	}
+
+	err := tm.inTx(ctx, func(tx *taskTx) error {
+		if len(valid) == 0 {
+			return nil
+		}
+		changed, err := tx.LockAndChange(ctx, valid, false, c)
+		if err != nil {
+			tm.kafkaLogger.Logger().Error().Err(err).Int("count", len(valid)).Msg("batch exec error")
+			return fmt.Errorf("batch error %s", err)
+		}
+		for j, ch := range changed {
+			if ch.After == nil {
+				results[indexes[j]].Error = "task not found"
+				continue
+			}
+			tm.recordEvent(ctx, tx, op, valid[j], ch.Before, ch.After)
+			results[indexes[j]].Ok = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.BatchResult{Results: results}, nil
+}
@@ -0,0 +1,14 @@
+package taskmanager
+
+import (
+	"db-service/internal/cache"
+	"db-service/internal/repository"
+)
+
+// taskTx is a transaction that remembers the tasks it changed. inTx adds their events in one call before commit
+// and writes them to the cache after it
+type taskTx struct {
+	repository.Tx
+	events  []repository.Event
+	changes []cache.Entry
+}
@@ -0,0 +1,88 @@
+package taskmanager
+
+import (
+	"context"
+	"db-service/internal/cache"
+	"db-service/internal/repository"
+	"fmt"
+	"slices"
+	pb "task-api/taskpb/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	opCreate  = "create"
+	opUpdate  = "update"
+	opDone    = "done"
+	opDelete  = "delete"
+	opRestore = "restore"
+)
+
+// actorFrom returns the x-user-id forwarded by api-service
+func actorFrom(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("x-user-id"); len(v) > 0 && v[0] != "" {
+			return v[0]
+		}
+	}
+	return "anonymous"
+}
+
+// changeTask locks the task, applies c and records the change in task_events. Deleted tasks are only changed
+// by restore. It returns false if there is no such task and an ABORTED error if expected is set and the task
+// has none of its versions
+func (tm *TaskManager) changeTask(ctx context.Context, tx *taskTx, op, id string, expected []int64, c repository.Change) (bool, error) {
+	before, ok, err := tx.Lock(ctx, id, op == opRestore)
+	if err != nil {
+		return false, fmt.Errorf("select for %s error %s", op, err)
+	}
+	if !ok {
+		return false, nil
+	}
+	if expected != nil && !slices.Contains(expected, before.Version) {
+		return false, status.Errorf(codes.Aborted, "task %s has version %d, expected %v", id, before.Version, expected)
+	}
+
+	after, err := tx.Change(ctx, id, c)
+	if err != nil {
+		return false, fmt.Errorf("%s error %s", op, err)
+	}
+
+	tm.recordEvent(ctx, tx, op, id, before, after)
+	return true, nil
+}
+
+// recordEvent queues the event of a change and remembers after for the cache
+func (tm *TaskManager) recordEvent(ctx context.Context, tx *taskTx, op, id string, before, after *pb.Task) {
+	tx.events = append(tx.events, repository.Event{
+		TaskID:    id,
+		Actor:     actorFrom(ctx),
+		Operation: op,
+		Before:    before,
+		After:     after,
+	})
+	tx.changes = append(tx.changes, cache.Entry{Task: after, Deleted: op == opDelete})
+}
+
+func (tm *TaskManager) GetHistory(ctx context.Context, in *pb.TaskID) (*pb.TaskHistory, error) {
+	tm.kafkaLogger.Logger().Info().Str("id", in.ID).Msg("received GetHistory request")
+
+	if in.ID == "" {
+		tm.kafkaLogger.Logger().Warn().Msg("empty ID provided in GetHistory")
+		return nil, status.Errorf(codes.InvalidArgument, "id is empty")
+	}
+	if !validID(in.ID) {
+		return &pb.TaskHistory{}, nil
+	}
+
+	events, err := tm.repo.History(ctx, in.ID)
+	if err != nil {
+		tm.kafkaLogger.Logger().Error().Err(err).Str("id", in.ID).Msg("select from task_events error")
+		return nil, err
+	}
+
+	return &pb.TaskHistory{Events: events}, nil
+}
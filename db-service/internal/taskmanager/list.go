@@ -0,0 +1,85 @@
+package taskmanager
+
+import (
+	"context"
+	pb "task-api/taskpb/v1"
+	"time"
+)
+
+const (
+	// how long other replicas wait for the rebuilt list before they query the database themselves
+	listLockWait = 500 * time.Millisecond
+	listPollStep = 25 * time.Millisecond
+	// bounds the shared load of List, the callers that started it may have no deadline or be gone
+	listLoadTimeout = 10 * time.Second
+)
+
+// loadList reads the list from the database and caches it. Only the caller that takes the list lock
+// queries the database, the others wait until it has rebuilt the cache
+func (tm *TaskManager) loadList(ctx context.Context) ([]*pb.Task, error) {
+	unlock, locked, err := tm.cache.LockList(ctx)
+	if err != nil {
+		tm.kafkaLogger.Logger().Warn().Err(err).Msg("failed to take task list lock in cache")
+	}
+	if err == nil && !locked {
+		if tasks, ok := tm.waitForList(ctx); ok {
+			tm.kafkaLogger.Logger().Info().Msg("get TaskList rebuilt by another replica")
+			return tasks, nil
+		}
+		tm.kafkaLogger.Logger().Warn().Msg("task list was not rebuilt in time, querying the database")
+	}
+	if locked {
+		defer unlock()
+	}
+
+	gen, genErr := tm.cache.Gen(ctx)
+
+	tasks, err := tm.queryList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if genErr == nil {
+		if err := tm.cache.SetList(ctx, tasks, gen); err != nil {
+			tm.kafkaLogger.Logger().Warn().Err(err).Msg("failed to cache task list")
+		} else {
+			tm.kafkaLogger.Logger().Info().Msg("cached task list")
+		}
+	}
+	return tasks, nil
+}
+
+func (tm *TaskManager) queryList(ctx context.Context) ([]*pb.Task, error) {
+	tm.kafkaLogger.Logger().Info().Msg("query select from tasks")
+	tasks, err := tm.repo.List(ctx)
+	if err != nil {
+		tm.kafkaLogger.Logger().Error().Err(err).Msg("select from tasks error")
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// waitForList polls the cache until the list shows up or listLockWait is over
+func (tm *TaskManager) waitForList(ctx context.Context) ([]*pb.Task, bool) {
+	ticker := time.NewTicker(listPollStep)
+	defer ticker.Stop()
+	timeout := time.After(listLockWait)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-timeout:
+			return nil, false
+		case <-ticker.C:
+		}
+
+		tasks, ok, err := tm.cache.List(ctx)
+		if err != nil {
+			return nil, false
+		}
+		if ok {
+			return tasks, true
+		}
+	}
+}
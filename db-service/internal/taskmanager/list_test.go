@@ -0,0 +1,194 @@
+package taskmanager
+
+import (
+	"context"
+	"db-service/internal/cache"
+	"db-service/internal/pkg/logger"
+	"db-service/internal/repository"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	pb "task-api/taskpb/v1"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// countingRepo counts the List calls that reach the repository
+type countingRepo struct {
+	repository.TaskRepository
+	lists atomic.Int64
+	// release, if set, holds List until it is closed
+	release chan struct{}
+}
+
+func (r *countingRepo) List(ctx context.Context) ([]*pb.Task, error) {
+	r.lists.Add(1)
+	if r.release != nil {
+		<-r.release
+	}
+	return r.TaskRepository.List(ctx)
+}
+
+// waitFor polls cond until it holds or a few seconds are over
+func waitFor(tb testing.TB, cond func() bool) {
+	tb.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			tb.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// blobCache drops everything on every write like the task_list blob that the per-task cache replaced
+type blobCache struct {
+	cache.Cache
+}
+
+func (c blobCache) PutTasks(ctx context.Context, entries []cache.Entry, write bool) error {
+	if write {
+		return c.Flush(ctx)
+	}
+	return c.Cache.PutTasks(ctx, entries, write)
+}
+
+func newTestManager(repo repository.TaskRepository, c cache.Cache) *TaskManager {
+	return NewTaskManager(repo, c, logger.NewNopLogger(), 100)
+}
+
+// seedTasks creates n tasks in repo and returns their ids
+func seedTasks(tb testing.TB, repo repository.TaskRepository, n int) []string {
+	tb.Helper()
+
+	drafts := make([]*pb.CreateTask, n)
+	for i := range drafts {
+		drafts[i] = &pb.CreateTask{Header: "task " + strconv.Itoa(i), Body: "body"}
+	}
+	var ids []string
+	err := repo.InTx(context.Background(), func(tx repository.Tx) error {
+		created, err := tx.Create(context.Background(), drafts)
+		for _, t := range created {
+			ids = append(ids, t.ID)
+		}
+		return err
+	})
+	if err != nil {
+		tb.Fatalf("seed tasks: %v", err)
+	}
+	return ids
+}
+
+// TestListConcurrentMissesShareOneLoad holds the first load until every caller has joined it,
+// all of them must get the list of that single load
+func TestListConcurrentMissesShareOneLoad(t *testing.T) {
+	const callers = 50
+	repo := &countingRepo{TaskRepository: repository.NewMemory(), release: make(chan struct{})}
+	seedTasks(t, repo, 10)
+	tm := newTestManager(repo, cache.NewLRU(100, time.Minute))
+	var joined atomic.Int64
+	tm.listJoined = func() { joined.Add(1) }
+
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			list, err := tm.List(context.Background(), &pb.TaskID{})
+			if err == nil && len(list.Tasks) != 10 {
+				err = fmt.Errorf("got %d tasks, want 10", len(list.Tasks))
+			}
+			errs <- err
+		}()
+	}
+
+	waitFor(t, func() bool { return joined.Load() == callers })
+	close(repo.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := repo.lists.Load(); n != 1 {
+		t.Fatalf("%d concurrent misses made %d repository List calls, want 1", callers, n)
+	}
+}
+
+// TestListCanceledDuringLoad cancels the caller while the shared load is blocked, List must return CANCELED
+// without waiting for it and the load must still fill the cache
+func TestListCanceledDuringLoad(t *testing.T) {
+	repo := &countingRepo{TaskRepository: repository.NewMemory(), release: make(chan struct{})}
+	seedTasks(t, repo, 3)
+	tm := newTestManager(repo, cache.NewLRU(100, time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := tm.List(ctx, &pb.TaskID{})
+		errs <- err
+	}()
+
+	waitFor(t, func() bool { return repo.lists.Load() == 1 })
+	cancel()
+	select {
+	case err := <-errs:
+		if status.Code(err) != codes.Canceled {
+			t.Fatalf("err = %v, want Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("List kept waiting for the load after its context was canceled")
+	}
+
+	close(repo.release)
+	waitFor(t, func() bool {
+		_, ok, _ := tm.cache.List(context.Background())
+		return ok
+	})
+	list, err := tm.List(context.Background(), &pb.TaskID{})
+	if err != nil || len(list.Tasks) != 3 {
+		t.Fatalf("List after the load = %v, %v, want 3 tasks", list, err)
+	}
+	if n := repo.lists.Load(); n != 1 {
+		t.Fatalf("repository List called %d times, want 1", n)
+	}
+}
+
+// BenchmarkListUnderWrites marks a task as done before every List. repo-lists/op is how often List reached the
+// repository: the per-task cache keeps the list cached through writes, the blob has to reload it every time
+func BenchmarkListUnderWrites(b *testing.B) {
+	caches := []struct {
+		name  string
+		cache func() cache.Cache
+	}{
+		{"cache=task", func() cache.Cache { return cache.NewLRU(10000, time.Minute) }},
+		{"cache=blob", func() cache.Cache { return blobCache{cache.NewLRU(10000, time.Minute)} }},
+	}
+	for _, c := range caches {
+		b.Run(c.name, func(b *testing.B) {
+			ctx := context.Background()
+			repo := &countingRepo{TaskRepository: repository.NewMemory()}
+			ids := seedTasks(b, repo, 200)
+			tm := newTestManager(repo, c.cache())
+
+			i := 0
+			for b.Loop() {
+				if _, err := tm.Done(ctx, &pb.TaskID{ID: ids[i%len(ids)]}); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := tm.List(ctx, &pb.TaskID{}); err != nil {
+					b.Fatal(err)
+				}
+				i++
+			}
+			b.ReportMetric(float64(repo.lists.Load())/float64(b.N), "repo-lists/op")
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package taskmanager
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	pb "task-api/taskpb/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultSearchPageSize = 20
+	maxSearchPageSize     = 100
+)
+
+func (tm *TaskManager) Search(ctx context.Context, in *pb.SearchRequest) (*pb.SearchResponse, error) {
+	tm.kafkaLogger.Logger().Info().Str("query", in.Query).Msg("received Search request")
+
+	query := strings.TrimSpace(in.Query)
+	if query == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "query is empty")
+	}
+
+	pageSize := int(in.PageSize)
+	switch {
+	case pageSize <= 0:
+		pageSize = defaultSearchPageSize
+	case pageSize > maxSearchPageSize:
+		pageSize = maxSearchPageSize
+	}
+
+	offset, err := decodePageToken(in.PageToken)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page token")
+	}
+
+	hits, total, err := tm.repo.Search(ctx, query, pageSize, offset)
+	if err != nil {
+		tm.kafkaLogger.Logger().Error().Err(err).Msg("search tasks error")
+		return nil, err
+	}
+
+	resp := &pb.SearchResponse{Hits: hits, Total: total}
+	if next := offset + len(resp.Hits); next < int(resp.Total) {
+		resp.NextPageToken = encodePageToken(next)
+	}
+
+	return resp, nil
+}
+
+// page tokens are opaque for clients, inside they hold the offset of the next page
+func encodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid offset %q", raw)
+	}
+	return offset, nil
+}
@@ -2,30 +2,44 @@ package taskmanager
 
 import (
 	"context"
-	"database/sql"
-	pb "db-service/api/proto"
+	"db-service/internal/cache"
 	"db-service/internal/pkg/logger"
-	"encoding/json"
-	"fmt"
-	"time"
+	"db-service/internal/repository"
+	pb "task-api/taskpb/v1"
 
-	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
 
-	_ "github.com/lib/pq"
+// changes for changeTask
+var (
+	doneChange    = repository.Change{IsDone: ptr(true)}
+	deleteChange  = repository.Change{Deleted: ptr(true)}
+	restoreChange = repository.Change{Deleted: ptr(false)}
 )
 
+func ptr[T any](v T) *T {
+	return &v
+}
+
 type TaskManager struct {
 	pb.UnimplementedTaskServiceServer
-	db          *sql.DB
-	redisClient *redis.Client
-	kafkaLogger *logger.KafkaLogger
+	repo         repository.TaskRepository
+	cache        cache.Cache
+	kafkaLogger  *logger.KafkaLogger
+	maxBatchSize int
+	listGroup    singleflight.Group
+	// listJoined, if set, is called by List once it waits for the shared load, tests count the callers with it
+	listJoined func()
 }
 
-func NewTaskManager(db *sql.DB, redisClient *redis.Client, logger *logger.KafkaLogger) *TaskManager {
+func NewTaskManager(repo repository.TaskRepository, cache cache.Cache, logger *logger.KafkaLogger, maxBatchSize int) *TaskManager {
 	return &TaskManager{
-		db:          db,
-		redisClient: redisClient,
-		kafkaLogger: logger,
+		repo:         repo,
+		cache:        cache,
+		kafkaLogger:  logger,
+		maxBatchSize: maxBatchSize,
 	}
 }
 
@@ -33,20 +47,22 @@ func (tm *TaskManager) Create(ctx context.Context, in *pb.CreateTask) (*pb.Nothi
 	tm.kafkaLogger.Logger().Info().Str("header", in.Header).Str("body", in.Body).Msg("received Create request")
 
 	if in.Header == "" && in.Body == "" {
-		return nil, fmt.Errorf("header is nil: %s or body is nil: %s", in.Header, in.Body)
+		tm.kafkaLogger.Logger().Warn().Msg("empty header and body provided in Create")
+		return nil, status.Errorf(codes.InvalidArgument, "header and body are empty")
 	}
 
 	tm.kafkaLogger.Logger().Info().Msg("query insert into tasks")
-	_, err := tm.db.Exec("INSERT INTO tasks(header, body) VALUES ($1, $2)", in.Header, in.Body)
+	err := tm.inTx(ctx, func(tx *taskTx) error {
+		created, err := tx.Create(ctx, []*pb.CreateTask{in})
+		if err != nil {
+			tm.kafkaLogger.Logger().Error().Err(err).Msg("insert into tasks insert error")
+			return err
+		}
+		tm.recordEvent(ctx, tx, opCreate, created[0].ID, nil, created[0])
+		return nil
+	})
 	if err != nil {
-		tm.kafkaLogger.Logger().Error().Err(err).Msg("insert into tasks insert error")
-		return &pb.Nothing{Dummy: false}, fmt.Errorf("insert into tasks insert error %s", err)
-	}
-
-	if err := tm.redisClient.Del(ctx, "task_list").Err(); err != nil {
-		tm.kafkaLogger.Logger().Warn().Err(err).Msg("failed to delete task_list from Redis")
-	} else {
-		tm.kafkaLogger.Logger().Info().Msg("deleted task_list from Redis")
+		return &pb.Nothing{Dummy: false}, err
 	}
 
 	tm.kafkaLogger.Logger().Info().Msg("task successfully inserted into DB")
@@ -56,69 +72,69 @@ func (tm *TaskManager) Create(ctx context.Context, in *pb.CreateTask) (*pb.Nothi
 func (tm *TaskManager) List(ctx context.Context, in *pb.TaskID) (*pb.TaskList, error) {
 	tm.kafkaLogger.Logger().Info().Msg("received List request")
 
-	cacheKey := "task_list"
-
-	tm.kafkaLogger.Logger().Info().Msg("attempting to get task_list from Redis cache")
-	val, err := tm.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var cachedTasks pb.TaskList
-		if jsonErr := json.Unmarshal([]byte(val), &cachedTasks); jsonErr == nil {
-			tm.kafkaLogger.Logger().Info().Msg("get TaskList from Redis cache")
-			return &cachedTasks, nil
-		}
-	}
-
-	tm.kafkaLogger.Logger().Info().Msg("query select from tasks")
-	rows, err := tm.db.Query("SELECT id, header, body, isdone FROM tasks")
+	tm.kafkaLogger.Logger().Info().Msg("attempting to get task list from cache")
+	cached, ok, err := tm.cache.List(ctx)
 	if err != nil {
-		tm.kafkaLogger.Logger().Error().Err(err).Msg("select from tasks error")
-
-		return nil, err
+		tm.kafkaLogger.Logger().Warn().Err(err).Msg("failed to get task list from cache")
 	}
-	defer rows.Close()
-
-	var tasks []*pb.Task
-	for rows.Next() {
-		var t pb.Task
-		err := rows.Scan(&t.ID, &t.Header, &t.Body, &t.IsDone)
-		if err != nil {
-			tm.kafkaLogger.Logger().Error().Err(err).Msg("rows scan error")
-			return nil, err
-		}
-		tasks = append(tasks, &t)
+	if ok {
+		tm.kafkaLogger.Logger().Info().Msg("get TaskList from cache")
+		return &pb.TaskList{Tasks: cached}, nil
 	}
 
-	result := &pb.TaskList{Tasks: tasks}
-
-	data, err := json.Marshal(result)
-	if err != nil {
-		tm.kafkaLogger.Logger().Error().Err(err).Msg("can't marshal List")
-		return nil, fmt.Errorf("can't marshal List, %v", err)
+	// concurrent misses of this replica share one load. It outlives a canceled caller because the others
+	// still wait for it, listLoadTimeout bounds it instead
+	ch := tm.listGroup.DoChan("list", func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), listLoadTimeout)
+		defer cancel()
+		return tm.loadList(loadCtx)
+	})
+	if tm.listJoined != nil {
+		tm.listJoined()
+	}
+	select {
+	case <-ctx.Done():
+		tm.kafkaLogger.Logger().Warn().Err(ctx.Err()).Msg("List canceled while loading the task list")
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		if res.Shared {
+			tm.kafkaLogger.Logger().Info().Msg("shared task list load with concurrent List calls")
+		}
+		return &pb.TaskList{Tasks: res.Val.([]*pb.Task)}, nil
 	}
-
-	tm.redisClient.Set(ctx, cacheKey, data, 1*time.Minute)
-	tm.kafkaLogger.Logger().Info().Msg("cached task_list to Redis for 1 minute")
-
-	return result, nil
 }
 func (tm *TaskManager) Delete(ctx context.Context, in *pb.TaskID) (*pb.Nothing, error) {
 	tm.kafkaLogger.Logger().Info().Str("id", in.ID).Msg("received Delete request")
 	if in.ID == "" {
 		tm.kafkaLogger.Logger().Warn().Msg("empty ID provided in Delete")
-		return nil, fmt.Errorf("id is empty %s", in.ID)
+		return nil, status.Errorf(codes.InvalidArgument, "id is empty")
+	}
+	if !validID(in.ID) {
+		return nil, status.Errorf(codes.NotFound, "task %s not found", in.ID)
 	}
 
-	tm.kafkaLogger.Logger().Info().Str("id", in.ID).Msg("executing delete from DB")
-	_, err := tm.db.Exec("DELETE FROM tasks WHERE id = $1;", in.ID)
+	expected, err := expectedVersion(ctx, in.ExpectedVersion)
 	if err != nil {
-		tm.kafkaLogger.Logger().Error().Err(err).Str("id", in.ID).Msg("failed to delete task")
-		return &pb.Nothing{Dummy: false}, fmt.Errorf("delete error %s", err)
+		return nil, err
 	}
 
-	if err := tm.redisClient.Del(ctx, "task_list").Err(); err != nil {
-		tm.kafkaLogger.Logger().Warn().Err(err).Msg("failed to delete task_list from Redis")
-	} else {
-		tm.kafkaLogger.Logger().Info().Msg("deleted task_list from Redis")
+	tm.kafkaLogger.Logger().Info().Str("id", in.ID).Msg("moving task to trash")
+	var found bool
+	err = tm.inTx(ctx, func(tx *taskTx) error {
+		var err error
+		found, err = tm.changeTask(ctx, tx, opDelete, in.ID, expected, deleteChange)
+		return err
+	})
+	if err != nil {
+		tm.kafkaLogger.Logger().Error().Err(err).Str("id", in.ID).Msg("failed to delete task")
+		return &pb.Nothing{Dummy: false}, err
+	}
+	if !found {
+		tm.kafkaLogger.Logger().Warn().Str("id", in.ID).Msg("task not found")
+		return nil, status.Errorf(codes.NotFound, "task %s not found", in.ID)
 	}
 
 	return &pb.Nothing{Dummy: false}, nil
@@ -128,21 +144,113 @@ func (tm *TaskManager) Done(ctx context.Context, in *pb.TaskID) (*pb.Nothing, er
 
 	if in.ID == "" {
 		tm.kafkaLogger.Logger().Warn().Msg("empty ID provided in Done")
-		return nil, fmt.Errorf("id is empty %s", in.ID)
+		return nil, status.Errorf(codes.InvalidArgument, "id is empty")
+	}
+	if !validID(in.ID) {
+		return nil, status.Errorf(codes.NotFound, "task %s not found", in.ID)
+	}
+
+	expected, err := expectedVersion(ctx, in.ExpectedVersion)
+	if err != nil {
+		return nil, err
 	}
 
 	tm.kafkaLogger.Logger().Info().Str("id", in.ID).Msg("marking task as done")
-	_, err := tm.db.Exec("UPDATE tasks SET isdone = true WHERE id = $1;", in.ID)
+	var found bool
+	err = tm.inTx(ctx, func(tx *taskTx) error {
+		var err error
+		found, err = tm.changeTask(ctx, tx, opDone, in.ID, expected, doneChange)
+		return err
+	})
 	if err != nil {
 		tm.kafkaLogger.Logger().Error().Err(err).Str("id", in.ID).Msg("failed to mark task as done")
-		return &pb.Nothing{Dummy: false}, fmt.Errorf("update error %s", err)
+		return &pb.Nothing{Dummy: false}, err
+	}
+	if !found {
+		tm.kafkaLogger.Logger().Warn().Str("id", in.ID).Msg("task not found")
+		return nil, status.Errorf(codes.NotFound, "task %s not found", in.ID)
 	}
 
 	tm.kafkaLogger.Logger().Info().Str("id", in.ID).Msg("task successfully marked as done")
-	if err := tm.redisClient.Del(ctx, "task_list").Err(); err != nil {
-		tm.kafkaLogger.Logger().Warn().Err(err).Msg("failed to delete task_list from Redis")
-	} else {
-		tm.kafkaLogger.Logger().Info().Msg("deleted task_list from Redis")
+
+	return &pb.Nothing{Dummy: false}, nil
+}
+
+func (tm *TaskManager) Get(ctx context.Context, in *pb.TaskID) (*pb.Task, error) {
+	tm.kafkaLogger.Logger().Info().Str("id", in.ID).Msg("received Get request")
+
+	if in.ID == "" {
+		tm.kafkaLogger.Logger().Warn().Msg("empty ID provided in Get")
+		return nil, status.Errorf(codes.InvalidArgument, "id is empty")
+	}
+	if !validID(in.ID) {
+		return nil, status.Errorf(codes.NotFound, "task %s not found", in.ID)
+	}
+
+	if t, ok, err := tm.cache.GetTask(ctx, in.ID); err != nil {
+		tm.kafkaLogger.Logger().Warn().Err(err).Str("id", in.ID).Msg("failed to get task from cache")
+	} else if ok {
+		return t, nil
+	}
+
+	t, ok, err := tm.repo.Get(ctx, in.ID)
+	if err != nil {
+		tm.kafkaLogger.Logger().Error().Err(err).Str("id", in.ID).Msg("failed to get task")
+		return nil, err
+	}
+	if !ok {
+		tm.kafkaLogger.Logger().Warn().Str("id", in.ID).Msg("task not found")
+		return nil, status.Errorf(codes.NotFound, "task %s not found", in.ID)
+	}
+
+	if err := tm.cache.PutTasks(ctx, []cache.Entry{{Task: t}}, false); err != nil {
+		tm.kafkaLogger.Logger().Warn().Err(err).Str("id", in.ID).Msg("failed to cache task")
+	}
+	return t, nil
+}
+
+// Update changes only the fields that are set in the request
+func (tm *TaskManager) Update(ctx context.Context, in *pb.UpdateTask) (*pb.Nothing, error) {
+	tm.kafkaLogger.Logger().Info().Str("id", in.ID).Msg("received Update request")
+
+	if in.ID == "" {
+		tm.kafkaLogger.Logger().Warn().Msg("empty ID provided in Update")
+		return nil, status.Errorf(codes.InvalidArgument, "id is empty")
+	}
+	if !validID(in.ID) {
+		return nil, status.Errorf(codes.NotFound, "task %s not found", in.ID)
+	}
+	if in.Header == nil && in.Body == nil && in.IsDone == nil {
+		tm.kafkaLogger.Logger().Warn().Str("id", in.ID).Msg("nothing to update")
+		return nil, status.Errorf(codes.InvalidArgument, "nothing to update")
+	}
+	if in.Header != nil && in.Body != nil && *in.Header == "" && *in.Body == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "header and body are empty")
+	}
+
+	tm.kafkaLogger.Logger().Info().Str("id", in.ID).Msg("executing update in DB")
+	expected, err := expectedVersion(ctx, in.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var found bool
+	err = tm.inTx(ctx, func(tx *taskTx) error {
+		var err error
+		found, err = tm.changeTask(ctx, tx, opUpdate, in.ID, expected, repository.Change{
+			Header: in.Header,
+			Body:   in.Body,
+			IsDone: in.IsDone,
+		})
+		return err
+	})
+	if err != nil {
+		tm.kafkaLogger.Logger().Error().Err(err).Str("id", in.ID).Msg("failed to update task")
+		return &pb.Nothing{Dummy: false}, err
+	}
+	if !found {
+		tm.kafkaLogger.Logger().Warn().Str("id", in.ID).Msg("task not found")
+		return nil, status.Errorf(codes.NotFound, "task %s not found", in.ID)
 	}
 
 	return &pb.Nothing{Dummy: false}, nil
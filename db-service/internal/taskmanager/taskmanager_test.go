@@ -0,0 +1,328 @@
+package taskmanager
+
+import (
+	"context"
+	"db-service/internal/cache"
+	"db-service/internal/repository"
+	"fmt"
+	"slices"
+	"strconv"
+	pb "task-api/taskpb/v1"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// createTask creates one task through BatchCreate, which returns its id
+func createTask(t *testing.T, tm *TaskManager, header string) string {
+	t.Helper()
+	res, err := tm.BatchCreate(context.Background(), &pb.BatchCreateRequest{Tasks: []*pb.CreateTask{{Header: header}}})
+	if err != nil {
+		t.Fatalf("BatchCreate: %v", err)
+	}
+	if !res.Results[0].Ok {
+		t.Fatalf("BatchCreate: %s", res.Results[0].Error)
+	}
+	return res.Results[0].ID
+}
+
+func wantCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	if status.Code(err) != code {
+		t.Fatalf("err = %v, want %s", err, code)
+	}
+}
+
+func listIDs(t *testing.T, tm *TaskManager) []string {
+	t.Helper()
+	list, err := tm.List(context.Background(), &pb.TaskID{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var ids []string
+	for _, task := range list.Tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func TestValidation(t *testing.T) {
+	tm := newTestManager(repository.NewMemory(), cache.NewNop())
+	ctx := context.Background()
+	empty := ""
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"create without header and body", func() error { _, err := tm.Create(ctx, &pb.CreateTask{}); return err }},
+		{"get without id", func() error { _, err := tm.Get(ctx, &pb.TaskID{}); return err }},
+		{"update without id", func() error {
+			_, err := tm.Update(ctx, &pb.UpdateTask{Header: ptr("h")})
+			return err
+		}},
+		{"update without fields", func() error { _, err := tm.Update(ctx, &pb.UpdateTask{ID: "1"}); return err }},
+		{"update to empty header and body", func() error {
+			_, err := tm.Update(ctx, &pb.UpdateTask{ID: "1", Header: &empty, Body: &empty})
+			return err
+		}},
+		{"delete without id", func() error { _, err := tm.Delete(ctx, &pb.TaskID{}); return err }},
+		{"done without id", func() error { _, err := tm.Done(ctx, &pb.TaskID{}); return err }},
+		{"restore without id", func() error { _, err := tm.Restore(ctx, &pb.TaskID{}); return err }},
+		{"empty batch", func() error { _, err := tm.BatchCreate(ctx, &pb.BatchCreateRequest{}); return err }},
+		{"batch over the limit", func() error {
+			_, err := tm.BatchDone(ctx, &pb.BatchTaskIDs{IDs: make([]string, tm.maxBatchSize+1)})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantCode(t, tt.call(), codes.InvalidArgument)
+		})
+	}
+}
+
+func TestBatchReportsInvalidItems(t *testing.T) {
+	tm := newTestManager(repository.NewMemory(), cache.NewNop())
+	ctx := context.Background()
+	id := createTask(t, tm, "a")
+
+	created, err := tm.BatchCreate(ctx, &pb.BatchCreateRequest{Tasks: []*pb.CreateTask{{Header: "b"}, {}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created.Results[0].Ok || created.Results[1].Ok {
+		t.Fatalf("BatchCreate results = %v, want only the first to succeed", created.Results)
+	}
+
+	// 99999999999 doesn't fit the integer id column, Postgres would abort the whole batch
+	done, err := tm.BatchDone(ctx, &pb.BatchTaskIDs{IDs: []string{id, "", "x", "99999999999", "404"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"", "id is empty", "task not found", "task not found", "task not found"} {
+		if r := done.Results[i]; r.Error != want || r.Ok != (want == "") {
+			t.Fatalf("BatchDone result %d = %v, want error %q", i, r, want)
+		}
+	}
+}
+
+func TestDoneDeleteRestore(t *testing.T) {
+	tm := newTestManager(repository.NewMemory(), cache.NewNop())
+	ctx := context.Background()
+	id := createTask(t, tm, "a")
+
+	if _, err := tm.Done(ctx, &pb.TaskID{ID: id}); err != nil {
+		t.Fatalf("Done: %v", err)
+	}
+	task, err := tm.Get(ctx, &pb.TaskID{ID: id})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !task.IsDone || task.Version != 2 {
+		t.Fatalf("after Done got %v, want done at version 2", task)
+	}
+
+	if _, err := tm.Delete(ctx, &pb.TaskID{ID: id}); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	_, err = tm.Get(ctx, &pb.TaskID{ID: id})
+	wantCode(t, err, codes.NotFound)
+	if ids := listIDs(t, tm); slices.Contains(ids, id) {
+		t.Fatalf("List returned deleted task %s", id)
+	}
+	trash, err := tm.ListTrash(ctx, &pb.TaskID{})
+	if err != nil || len(trash.Tasks) != 1 || trash.Tasks[0].Task.ID != id {
+		t.Fatalf("ListTrash = %v, %v, want task %s", trash, err, id)
+	}
+
+	// trashed tasks are only changed by Restore
+	_, err = tm.Delete(ctx, &pb.TaskID{ID: id})
+	wantCode(t, err, codes.NotFound)
+	_, err = tm.Done(ctx, &pb.TaskID{ID: id})
+	wantCode(t, err, codes.NotFound)
+
+	if _, err := tm.Restore(ctx, &pb.TaskID{ID: id}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if ids := listIDs(t, tm); !slices.Contains(ids, id) {
+		t.Fatalf("List = %v, want restored task %s", ids, id)
+	}
+	_, err = tm.Restore(ctx, &pb.TaskID{ID: id})
+	wantCode(t, err, codes.NotFound)
+
+	history, err := tm.GetHistory(ctx, &pb.TaskID{ID: id})
+	if err != nil {
+		t.Fatalf("GetHistory: %v", err)
+	}
+	var ops []string
+	for _, e := range history.Events {
+		ops = append(ops, e.Operation)
+	}
+	if want := []string{opCreate, opDone, opDelete, opRestore}; !slices.Equal(ops, want) {
+		t.Fatalf("history = %v, want %v", ops, want)
+	}
+}
+
+func TestVersionConflict(t *testing.T) {
+	tm := newTestManager(repository.NewMemory(), cache.NewNop())
+	ctx := context.Background()
+	id := createTask(t, tm, "a")
+
+	_, err := tm.Update(ctx, &pb.UpdateTask{ID: id, Header: ptr("b"), ExpectedVersion: ptr[int64](5)})
+	wantCode(t, err, codes.Aborted)
+	_, err = tm.Done(ctx, &pb.TaskID{ID: id, ExpectedVersion: ptr[int64](5)})
+	wantCode(t, err, codes.Aborted)
+
+	ifMatch := func(etag string) context.Context {
+		return metadata.NewIncomingContext(ctx, metadata.MD{"if-match": {etag}})
+	}
+	if _, err := tm.Update(ifMatch(`"1"`), &pb.UpdateTask{ID: id, Header: ptr("b")}); err != nil {
+		t.Fatalf("Update with the current ETag: %v", err)
+	}
+	// the ETag of version 1 is stale now
+	_, err = tm.Delete(ifMatch(`"1"`), &pb.TaskID{ID: id})
+	wantCode(t, err, codes.Aborted)
+
+	task, err := tm.Get(ctx, &pb.TaskID{ID: id})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if task.Header != "b" || task.IsDone || task.Version != 2 {
+		t.Fatalf("got %v, want only the update applied", task)
+	}
+}
+
+func TestCacheWriteThrough(t *testing.T) {
+	repo := &countingRepo{TaskRepository: repository.NewMemory()}
+	taskCache := cache.NewLRU(100, time.Minute)
+	tm := newTestManager(repo, taskCache)
+	ctx := context.Background()
+	a, b := createTask(t, tm, "a"), createTask(t, tm, "b")
+
+	if ids := listIDs(t, tm); !slices.Equal(ids, []string{a, b}) {
+		t.Fatalf("List = %v, want [%s %s]", ids, a, b)
+	}
+
+	if _, err := tm.Done(ctx, &pb.TaskID{ID: a}); err != nil {
+		t.Fatalf("Done: %v", err)
+	}
+	cached, ok, err := taskCache.GetTask(ctx, a)
+	if err != nil || !ok || !cached.IsDone || cached.Version != 2 {
+		t.Fatalf("cached task = %v, %v, %v, want done at version 2", cached, ok, err)
+	}
+
+	if _, err := tm.Delete(ctx, &pb.TaskID{ID: b}); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok, _ := taskCache.GetTask(ctx, b); ok {
+		t.Fatalf("deleted task %s is still cached", b)
+	}
+
+	list, err := tm.List(ctx, &pb.TaskID{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list.Tasks) != 1 || list.Tasks[0].ID != a || !list.Tasks[0].IsDone {
+		t.Fatalf("List = %v, want only the done task %s", list.Tasks, a)
+	}
+	if n := repo.lists.Load(); n != 1 {
+		t.Fatalf("List reached the repository %d times, want 1: writes must update the cached list", n)
+	}
+}
+
+func TestTagsAreKept(t *testing.T) {
+	tm := newTestManager(repository.NewMemory(), cache.NewLRU(100, time.Minute))
+	ctx := context.Background()
+	tags := []string{"home", "urgent"}
+	in := &pb.CreateTask{Header: "a", Tags: tags}
+
+	if _, err := tm.Create(ctx, in); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	// the stored task must not share the slice of the request
+	tags[0] = "changed"
+
+	ids := listIDs(t, tm)
+	if _, err := tm.BatchDone(ctx, &pb.BatchTaskIDs{IDs: ids}); err != nil {
+		t.Fatalf("BatchDone: %v", err)
+	}
+	task, err := tm.Get(ctx, &pb.TaskID{ID: ids[0]})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !task.IsDone || !slices.Equal(task.Tags, []string{"home", "urgent"}) {
+		t.Fatalf("got %v, want a done task with tags [home urgent]", task)
+	}
+}
+
+// intIDRepo fails like Postgres on ids that don't fit the integer id column, Memory would just find no task
+type intIDRepo struct {
+	repository.TaskRepository
+}
+
+func checkID(id string) error {
+	if _, err := strconv.ParseInt(id, 10, 32); err != nil {
+		return fmt.Errorf("invalid input syntax for type integer: %q", id)
+	}
+	return nil
+}
+
+func (r intIDRepo) Get(ctx context.Context, id string) (*pb.Task, bool, error) {
+	if err := checkID(id); err != nil {
+		return nil, false, err
+	}
+	return r.TaskRepository.Get(ctx, id)
+}
+
+func (r intIDRepo) History(ctx context.Context, id string) ([]*pb.TaskEvent, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+	return r.TaskRepository.History(ctx, id)
+}
+
+func (r intIDRepo) InTx(ctx context.Context, fn func(tx repository.Tx) error) error {
+	return r.TaskRepository.InTx(ctx, func(tx repository.Tx) error {
+		return fn(intIDTx{tx})
+	})
+}
+
+type intIDTx struct {
+	repository.Tx
+}
+
+func (tx intIDTx) Lock(ctx context.Context, id string, deleted bool) (*pb.Task, bool, error) {
+	if err := checkID(id); err != nil {
+		return nil, false, err
+	}
+	return tx.Tx.Lock(ctx, id, deleted)
+}
+
+func TestMalformedIDsAreNotFound(t *testing.T) {
+	tm := newTestManager(intIDRepo{repository.NewMemory()}, cache.NewNop())
+	ctx := context.Background()
+
+	for _, id := range []string{"abc", "99999999999"} {
+		t.Run(id, func(t *testing.T) {
+			_, err := tm.Get(ctx, &pb.TaskID{ID: id})
+			wantCode(t, err, codes.NotFound)
+			_, err = tm.Update(ctx, &pb.UpdateTask{ID: id, Header: ptr("h")})
+			wantCode(t, err, codes.NotFound)
+			_, err = tm.Delete(ctx, &pb.TaskID{ID: id})
+			wantCode(t, err, codes.NotFound)
+			_, err = tm.Done(ctx, &pb.TaskID{ID: id})
+			wantCode(t, err, codes.NotFound)
+			_, err = tm.Restore(ctx, &pb.TaskID{ID: id})
+			wantCode(t, err, codes.NotFound)
+
+			history, err := tm.GetHistory(ctx, &pb.TaskID{ID: id})
+			if err != nil || len(history.Events) != 0 {
+				t.Fatalf("GetHistory = %v, %v, want no events", history, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package taskmanager
+
+import (
+	"context"
+	pb "task-api/taskpb/v1"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (tm *TaskManager) Restore(ctx context.Context, in *pb.TaskID) (*pb.Nothing, error) {
+	tm.kafkaLogger.Logger().Info().Str("id", in.ID).Msg("received Restore request")
+
+	if in.ID == "" {
+		tm.kafkaLogger.Logger().Warn().Msg("empty ID provided in Restore")
+		return nil, status.Errorf(codes.InvalidArgument, "id is empty")
+	}
+	if !validID(in.ID) {
+		return nil, status.Errorf(codes.NotFound, "task %s not found in trash", in.ID)
+	}
+
+	expected, err := expectedVersion(ctx, in.ExpectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	var found bool
+	err = tm.inTx(ctx, func(tx *taskTx) error {
+		var err error
+		found, err = tm.changeTask(ctx, tx, opRestore, in.ID, expected, restoreChange)
+		return err
+	})
+	if err != nil {
+		tm.kafkaLogger.Logger().Error().Err(err).Str("id", in.ID).Msg("failed to restore task")
+		return nil, err
+	}
+	if !found {
+		tm.kafkaLogger.Logger().Warn().Str("id", in.ID).Msg("task not found in trash")
+		return nil, status.Errorf(codes.NotFound, "task %s not found in trash", in.ID)
+	}
+
+	return &pb.Nothing{Dummy: false}, nil
+}
+
+// ListTrash returns deleted tasks, the most recently deleted first. It is not cached
+func (tm *TaskManager) ListTrash(ctx context.Context, in *pb.TaskID) (*pb.TrashList, error) {
+	tm.kafkaLogger.Logger().Info().Msg("received ListTrash request")
+
+	tasks, err := tm.repo.Trash(ctx)
+	if err != nil {
+		tm.kafkaLogger.Logger().Error().Err(err).Msg("select trash error")
+		return nil, err
+	}
+
+	return &pb.TrashList{Tasks: tasks}, nil
+}
+
+// Purge hard deletes tasks that have been in the trash for longer than retention
+func (tm *TaskManager) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	return tm.repo.Purge(ctx, time.Now().Add(-retention))
+}
+
+// RunPurger calls Purge every interval until ctx is done
+func (tm *TaskManager) RunPurger(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := tm.Purge(ctx, retention)
+		if err != nil {
+			tm.kafkaLogger.Logger().Error().Err(err).Msg("failed to purge trash")
+		} else if n > 0 {
+			tm.kafkaLogger.Logger().Info().Int64("count", n).Msg("purged tasks from trash")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package taskmanager
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// expectedVersion returns the versions a write accepts: the ExpectedVersion field of the request, otherwise
+// the If-Match header forwarded by api-service. nil means the write does not check the version.
+// If-Match uses the strong comparison of RFC 9110: weak or malformed ETags never match, so a header without
+// any usable ETag is a failed precondition (ABORTED) rather than a bad request
+func expectedVersion(ctx context.Context, field *int64) ([]int64, error) {
+	if field != nil {
+		return []int64{*field}, nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	values := md.Get("if-match")
+	if len(values) == 0 {
+		return nil, nil
+	}
+	header := strings.Join(values, ",")
+	if strings.TrimSpace(header) == "*" {
+		return nil, nil
+	}
+
+	// ETags are the quoted version
+	var versions []int64
+	for _, etag := range strings.Split(header, ",") {
+		etag = strings.TrimSpace(etag)
+		if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+			continue
+		}
+		if v, err := strconv.ParseInt(etag[1:len(etag)-1], 10, 64); err == nil {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, status.Errorf(codes.Aborted, "If-Match %q matches no version", header)
+	}
+	return versions, nil
+}
@@ -0,0 +1,53 @@
+package taskmanager
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch []string
+		want    []int64
+		code    codes.Code
+	}{
+		{name: "no header"},
+		{name: "any", ifMatch: []string{"*"}},
+		{name: "strong", ifMatch: []string{`"3"`}, want: []int64{3}},
+		{name: "list", ifMatch: []string{`"3", "5"`}, want: []int64{3, 5}},
+		{name: "several headers", ifMatch: []string{`"3"`, `"5"`}, want: []int64{3, 5}},
+		{name: "weak is skipped", ifMatch: []string{`W/"3", "5"`}, want: []int64{5}},
+		{name: "only weak", ifMatch: []string{`W/"3"`}, code: codes.Aborted},
+		{name: "unquoted", ifMatch: []string{"3"}, code: codes.Aborted},
+		{name: "not a version", ifMatch: []string{`"abc"`}, code: codes.Aborted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.ifMatch != nil {
+				ctx = metadata.NewIncomingContext(ctx, metadata.MD{"if-match": tt.ifMatch})
+			}
+			got, err := expectedVersion(ctx, nil)
+			if status.Code(err) != tt.code {
+				t.Fatalf("err = %v, want %s", err, tt.code)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("versions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpectedVersionPrefersField(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{"if-match": {`"3"`}})
+	got, err := expectedVersion(ctx, ptr[int64](7))
+	if err != nil || !slices.Equal(got, []int64{7}) {
+		t.Fatalf("expectedVersion = %v, %v, want [7]", got, err)
+	}
+}
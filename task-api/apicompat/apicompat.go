@@ -0,0 +1,256 @@
+// Package apicompat checks that the TaskService API packages stay backwards compatible.
+//
+// The descriptors compiled into taskpb/v1 and taskpb/v2 are compared with the committed
+// descriptor set. Removing or renaming anything a client can depend on (messages, fields,
+// JSON names, field types, RPCs, HTTP bindings) is reported as a breaking change.
+package apicompat
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// versioned API packages, every file in them is checked
+const apiPackagePrefix = "taskpb."
+
+// Compare returns the breaking changes of current against baseline, nil if there are none
+func Compare(baseline []*descriptorpb.FileDescriptorProto, current map[string]*descriptorpb.FileDescriptorProto) []string {
+	var problems []string
+	for _, old := range baseline {
+		cur, ok := current[old.GetName()]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: file removed", old.GetName()))
+			continue
+		}
+		problems = append(problems, compareFiles(old, cur)...)
+	}
+	return problems
+}
+
+// CurrentFiles returns the API files linked into the binary, callers import the taskpb packages
+func CurrentFiles() map[string]*descriptorpb.FileDescriptorProto {
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		if strings.HasPrefix(string(fd.Package()), apiPackagePrefix) {
+			files[fd.Path()] = protodesc.ToFileDescriptorProto(fd)
+		}
+		return true
+	})
+	return files
+}
+
+func ReadSet(path string) ([]*descriptorpb.FileDescriptorProto, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	return set.File, nil
+}
+
+func WriteSet(path string, files map[string]*descriptorpb.FileDescriptorProto) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var set descriptorpb.FileDescriptorSet
+	for _, name := range names {
+		fd := proto.Clone(files[name]).(*descriptorpb.FileDescriptorProto)
+		fd.SourceCodeInfo = nil
+		set.File = append(set.File, fd)
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(&set)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func compareFiles(old, cur *descriptorpb.FileDescriptorProto) []string {
+	var problems []string
+	if old.GetPackage() != cur.GetPackage() {
+		problems = append(problems, fmt.Sprintf("%s: package changed from %s to %s", old.GetName(), old.GetPackage(), cur.GetPackage()))
+	}
+	problems = append(problems, compareMessages(old.GetPackage(), old.GetMessageType(), cur.GetMessageType())...)
+	problems = append(problems, compareEnums(old.GetPackage(), old.GetEnumType(), cur.GetEnumType())...)
+	problems = append(problems, compareServices(old.GetPackage(), old.GetService(), cur.GetService())...)
+	return problems
+}
+
+func compareMessages(scope string, old, cur []*descriptorpb.DescriptorProto) []string {
+	byName := make(map[string]*descriptorpb.DescriptorProto, len(cur))
+	for _, m := range cur {
+		byName[m.GetName()] = m
+	}
+
+	var problems []string
+	for _, om := range old {
+		name := scope + "." + om.GetName()
+		cm, ok := byName[om.GetName()]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("message %s removed", name))
+			continue
+		}
+		problems = append(problems, compareFields(name, om, cm)...)
+		problems = append(problems, compareMessages(name, om.GetNestedType(), cm.GetNestedType())...)
+		problems = append(problems, compareEnums(name, om.GetEnumType(), cm.GetEnumType())...)
+	}
+	return problems
+}
+
+func compareFields(msg string, old, cur *descriptorpb.DescriptorProto) []string {
+	byNumber := make(map[int32]*descriptorpb.FieldDescriptorProto, len(cur.GetField()))
+	for _, f := range cur.GetField() {
+		byNumber[f.GetNumber()] = f
+	}
+
+	var problems []string
+	for _, of := range old.GetField() {
+		name := fmt.Sprintf("%s.%s (%d)", msg, of.GetName(), of.GetNumber())
+		cf, ok := byNumber[of.GetNumber()]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("field %s removed", name))
+			continue
+		}
+		if of.GetName() != cf.GetName() {
+			problems = append(problems, fmt.Sprintf("field %s renamed to %s", name, cf.GetName()))
+		}
+		if of.GetJsonName() != cf.GetJsonName() {
+			problems = append(problems, fmt.Sprintf("field %s JSON name changed from %q to %q", name, of.GetJsonName(), cf.GetJsonName()))
+		}
+		if of.GetType() != cf.GetType() || of.GetTypeName() != cf.GetTypeName() {
+			problems = append(problems, fmt.Sprintf("field %s type changed", name))
+		}
+		if of.GetLabel() != cf.GetLabel() {
+			problems = append(problems, fmt.Sprintf("field %s label changed from %s to %s", name, of.GetLabel(), cf.GetLabel()))
+		}
+		if of.GetProto3Optional() != cf.GetProto3Optional() {
+			problems = append(problems, fmt.Sprintf("field %s presence changed", name))
+		}
+		if of.OneofIndex != nil && cf.OneofIndex != nil && !of.GetProto3Optional() &&
+			old.GetOneofDecl()[of.GetOneofIndex()].GetName() != cur.GetOneofDecl()[cf.GetOneofIndex()].GetName() {
+			problems = append(problems, fmt.Sprintf("field %s moved to another oneof", name))
+		}
+	}
+	return problems
+}
+
+func compareEnums(scope string, old, cur []*descriptorpb.EnumDescriptorProto) []string {
+	byName := make(map[string]*descriptorpb.EnumDescriptorProto, len(cur))
+	for _, e := range cur {
+		byName[e.GetName()] = e
+	}
+
+	var problems []string
+	for _, oe := range old {
+		name := scope + "." + oe.GetName()
+		ce, ok := byName[oe.GetName()]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("enum %s removed", name))
+			continue
+		}
+		values := make(map[string]int32, len(ce.GetValue()))
+		for _, v := range ce.GetValue() {
+			values[v.GetName()] = v.GetNumber()
+		}
+		for _, ov := range oe.GetValue() {
+			n, ok := values[ov.GetName()]
+			switch {
+			case !ok:
+				problems = append(problems, fmt.Sprintf("enum value %s.%s removed", name, ov.GetName()))
+			case n != ov.GetNumber():
+				problems = append(problems, fmt.Sprintf("enum value %s.%s number changed from %d to %d", name, ov.GetName(), ov.GetNumber(), n))
+			}
+		}
+	}
+	return problems
+}
+
+func compareServices(scope string, old, cur []*descriptorpb.ServiceDescriptorProto) []string {
+	byName := make(map[string]*descriptorpb.ServiceDescriptorProto, len(cur))
+	for _, s := range cur {
+		byName[s.GetName()] = s
+	}
+
+	var problems []string
+	for _, oldSvc := range old {
+		svc := scope + "." + oldSvc.GetName()
+		cs, ok := byName[oldSvc.GetName()]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("service %s removed", svc))
+			continue
+		}
+
+		methods := make(map[string]*descriptorpb.MethodDescriptorProto, len(cs.GetMethod()))
+		for _, m := range cs.GetMethod() {
+			methods[m.GetName()] = m
+		}
+		for _, om := range oldSvc.GetMethod() {
+			name := svc + "/" + om.GetName()
+			cm, ok := methods[om.GetName()]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("rpc %s removed", name))
+				continue
+			}
+			if om.GetInputType() != cm.GetInputType() || om.GetOutputType() != cm.GetOutputType() {
+				problems = append(problems, fmt.Sprintf("rpc %s request or response type changed", name))
+			}
+			if om.GetClientStreaming() != cm.GetClientStreaming() || om.GetServerStreaming() != cm.GetServerStreaming() {
+				problems = append(problems, fmt.Sprintf("rpc %s streaming changed", name))
+			}
+			for _, b := range httpBindings(om) {
+				if !slices.Contains(httpBindings(cm), b) {
+					problems = append(problems, fmt.Sprintf("rpc %s HTTP binding %q removed", name, b))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// httpBindings returns "METHOD /path body" for the google.api.http rule and its additional bindings
+func httpBindings(m *descriptorpb.MethodDescriptorProto) []string {
+	rule, ok := proto.GetExtension(m.GetOptions(), annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return nil
+	}
+
+	var bindings []string
+	for _, r := range append([]*annotations.HttpRule{rule}, rule.GetAdditionalBindings()...) {
+		var verb, path string
+		switch p := r.GetPattern().(type) {
+		case *annotations.HttpRule_Get:
+			verb, path = "GET", p.Get
+		case *annotations.HttpRule_Post:
+			verb, path = "POST", p.Post
+		case *annotations.HttpRule_Put:
+			verb, path = "PUT", p.Put
+		case *annotations.HttpRule_Patch:
+			verb, path = "PATCH", p.Patch
+		case *annotations.HttpRule_Delete:
+			verb, path = "DELETE", p.Delete
+		case *annotations.HttpRule_Custom:
+			verb, path = p.Custom.GetKind(), p.Custom.GetPath()
+		default:
+			continue
+		}
+		bindings = append(bindings, strings.TrimSpace(verb+" "+path+" "+r.GetBody()))
+	}
+	return bindings
+}
@@ -0,0 +1,54 @@
+package apicompat_test
+
+import (
+	"strings"
+	"testing"
+
+	"task-api/apicompat"
+	_ "task-api/taskpb/v1"
+	_ "task-api/taskpb/v2"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const baselinePath = "../descriptors/taskpb.binpb"
+
+func TestNoBreakingChanges(t *testing.T) {
+	baseline, err := apicompat.ReadSet(baselinePath)
+	if err != nil {
+		t.Fatalf("can't read descriptor set: %v", err)
+	}
+	if problems := apicompat.Compare(baseline, apicompat.CurrentFiles()); len(problems) > 0 {
+		t.Fatalf("breaking changes against %s:\n  %s", baselinePath, strings.Join(problems, "\n  "))
+	}
+}
+
+func TestRemovedFieldIsBreaking(t *testing.T) {
+	baseline, err := apicompat.ReadSet(baselinePath)
+	if err != nil {
+		t.Fatalf("can't read descriptor set: %v", err)
+	}
+
+	current := apicompat.CurrentFiles()
+	var removed string
+	for name, fd := range current {
+		fd = proto.Clone(fd).(*descriptorpb.FileDescriptorProto)
+		for _, m := range fd.GetMessageType() {
+			if len(m.GetField()) > 0 {
+				removed = m.GetName() + "." + m.GetField()[0].GetName()
+				m.Field = m.Field[1:]
+				break
+			}
+		}
+		current[name] = fd
+		if removed != "" {
+			break
+		}
+	}
+
+	problems := apicompat.Compare(baseline, current)
+	if len(problems) != 1 || !strings.Contains(problems[0], removed) {
+		t.Fatalf("removing %s reported %v", removed, problems)
+	}
+}
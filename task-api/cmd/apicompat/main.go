@@ -0,0 +1,30 @@
+// Command apicompat records the current TaskService API as the compatibility baseline that
+// apicompat_test.go compares with, run it after additive changes.
+//
+//	go run ./cmd/apicompat -update -against descriptors/taskpb.binpb
+package main
+
+import (
+	"flag"
+	"log"
+
+	"task-api/apicompat"
+	_ "task-api/taskpb/v1"
+	_ "task-api/taskpb/v2"
+)
+
+func main() {
+	against := flag.String("against", "descriptors/taskpb.binpb", "descriptor set to write")
+	update := flag.Bool("update", false, "overwrite the descriptor set with the current API")
+	flag.Parse()
+
+	if !*update {
+		log.Fatal("breaking changes are checked by go test ./apicompat, pass -update to record a new baseline")
+	}
+
+	current := apicompat.CurrentFiles()
+	if err := apicompat.WriteSet(*against, current); err != nil {
+		log.Fatalf("can't write descriptor set: %v", err)
+	}
+	log.Printf("wrote %d files to %s", len(current), *against)
+}
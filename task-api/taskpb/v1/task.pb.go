@@ -0,0 +1,1212 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: taskpb/v1/task.proto
+
+package taskpb
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateTask struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Header        string                 `protobuf:"bytes,1,opt,name=Header,json=header,proto3" json:"Header,omitempty"`
+	Body          string                 `protobuf:"bytes,2,opt,name=Body,json=body,proto3" json:"Body,omitempty"`
+	Tags          []string               `protobuf:"bytes,3,rep,name=Tags,json=tags,proto3" json:"Tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTask) Reset() {
+	*x = CreateTask{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTask) ProtoMessage() {}
+
+func (x *CreateTask) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTask.ProtoReflect.Descriptor instead.
+func (*CreateTask) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateTask) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
+}
+
+func (x *CreateTask) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *CreateTask) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type Task struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Header string                 `protobuf:"bytes,1,opt,name=Header,json=header,proto3" json:"Header,omitempty"`
+	Body   string                 `protobuf:"bytes,2,opt,name=Body,json=body,proto3" json:"Body,omitempty"`
+	ID     string                 `protobuf:"bytes,3,opt,name=ID,json=id,proto3" json:"ID,omitempty"`
+	IsDone bool                   `protobuf:"varint,4,opt,name=IsDone,json=isDone,proto3" json:"IsDone,omitempty"`
+	// incremented on every change of the task
+	Version       int64    `protobuf:"varint,5,opt,name=Version,json=version,proto3" json:"Version,omitempty"`
+	Tags          []string `protobuf:"bytes,6,rep,name=Tags,json=tags,proto3" json:"Tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Task) GetHeader() string {
+	if x != nil {
+		return x.Header
+	}
+	return ""
+}
+
+func (x *Task) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Task) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *Task) GetIsDone() bool {
+	if x != nil {
+		return x.IsDone
+	}
+	return false
+}
+
+func (x *Task) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Task) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type UpdateTask struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ID     string                 `protobuf:"bytes,1,opt,name=ID,json=id,proto3" json:"ID,omitempty"`
+	Header *string                `protobuf:"bytes,2,opt,name=Header,json=header,proto3,oneof" json:"Header,omitempty"`
+	Body   *string                `protobuf:"bytes,3,opt,name=Body,json=body,proto3,oneof" json:"Body,omitempty"`
+	IsDone *bool                  `protobuf:"varint,4,opt,name=IsDone,json=isDone,proto3,oneof" json:"IsDone,omitempty"`
+	// when set the update fails with ABORTED if the task has another version
+	ExpectedVersion *int64 `protobuf:"varint,5,opt,name=ExpectedVersion,json=expectedVersion,proto3,oneof" json:"ExpectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateTask) Reset() {
+	*x = UpdateTask{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTask) ProtoMessage() {}
+
+func (x *UpdateTask) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTask.ProtoReflect.Descriptor instead.
+func (*UpdateTask) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateTask) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *UpdateTask) GetHeader() string {
+	if x != nil && x.Header != nil {
+		return *x.Header
+	}
+	return ""
+}
+
+func (x *UpdateTask) GetBody() string {
+	if x != nil && x.Body != nil {
+		return *x.Body
+	}
+	return ""
+}
+
+func (x *UpdateTask) GetIsDone() bool {
+	if x != nil && x.IsDone != nil {
+		return *x.IsDone
+	}
+	return false
+}
+
+func (x *UpdateTask) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type TaskList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskList) Reset() {
+	*x = TaskList{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskList) ProtoMessage() {}
+
+func (x *TaskList) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskList.ProtoReflect.Descriptor instead.
+func (*TaskList) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TaskList) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type TaskID struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	ID    string                 `protobuf:"bytes,1,opt,name=ID,json=id,proto3" json:"ID,omitempty"`
+	// checked by Done, Delete and Restore like UpdateTask.ExpectedVersion
+	ExpectedVersion *int64 `protobuf:"varint,2,opt,name=ExpectedVersion,json=expectedVersion,proto3,oneof" json:"ExpectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TaskID) Reset() {
+	*x = TaskID{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskID) ProtoMessage() {}
+
+func (x *TaskID) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskID.ProtoReflect.Descriptor instead.
+func (*TaskID) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TaskID) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *TaskID) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+type BatchCreateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*CreateTask          `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateRequest) Reset() {
+	*x = BatchCreateRequest{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateRequest) ProtoMessage() {}
+
+func (x *BatchCreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateRequest) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchCreateRequest) GetTasks() []*CreateTask {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+type BatchTaskIDs struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IDs           []string               `protobuf:"bytes,1,rep,name=IDs,json=ids,proto3" json:"IDs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchTaskIDs) Reset() {
+	*x = BatchTaskIDs{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchTaskIDs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchTaskIDs) ProtoMessage() {}
+
+func (x *BatchTaskIDs) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchTaskIDs.ProtoReflect.Descriptor instead.
+func (*BatchTaskIDs) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchTaskIDs) GetIDs() []string {
+	if x != nil {
+		return x.IDs
+	}
+	return nil
+}
+
+// BatchItemResult is the outcome of one item, in the order of the request
+type BatchItemResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=Index,json=index,proto3" json:"Index,omitempty"`
+	ID            string                 `protobuf:"bytes,2,opt,name=ID,json=id,proto3" json:"ID,omitempty"`
+	Ok            bool                   `protobuf:"varint,3,opt,name=Ok,json=ok,proto3" json:"Ok,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=Error,json=error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchItemResult) Reset() {
+	*x = BatchItemResult{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItemResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItemResult) ProtoMessage() {}
+
+func (x *BatchItemResult) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItemResult.ProtoReflect.Descriptor instead.
+func (*BatchItemResult) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchItemResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchItemResult) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *BatchItemResult) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *BatchItemResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BatchItemResult     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchResult) GetResults() []*BatchItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=Query,json=q,proto3" json:"Query,omitempty"`
+	// defaults to 20, at most 100
+	PageSize int32 `protobuf:"varint,2,opt,name=PageSize,json=pageSize,proto3" json:"PageSize,omitempty"`
+	// NextPageToken of the previous page
+	PageToken     string `protobuf:"bytes,3,opt,name=PageToken,json=pageToken,proto3" json:"PageToken,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type SearchHit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Task  *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	Rank  float32                `protobuf:"fixed32,2,opt,name=Rank,json=rank,proto3" json:"Rank,omitempty"`
+	// fragments with the matched words wrapped in <b></b>
+	HeaderSnippet string `protobuf:"bytes,3,opt,name=HeaderSnippet,json=headerSnippet,proto3" json:"HeaderSnippet,omitempty"`
+	BodySnippet   string `protobuf:"bytes,4,opt,name=BodySnippet,json=bodySnippet,proto3" json:"BodySnippet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHit) Reset() {
+	*x = SearchHit{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHit) ProtoMessage() {}
+
+func (x *SearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHit.ProtoReflect.Descriptor instead.
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SearchHit) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *SearchHit) GetRank() float32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *SearchHit) GetHeaderSnippet() string {
+	if x != nil {
+		return x.HeaderSnippet
+	}
+	return ""
+}
+
+func (x *SearchHit) GetBodySnippet() string {
+	if x != nil {
+		return x.BodySnippet
+	}
+	return ""
+}
+
+type SearchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Hits  []*SearchHit           `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	// empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=NextPageToken,json=nextPageToken,proto3" json:"NextPageToken,omitempty"`
+	Total         int32  `protobuf:"varint,3,opt,name=Total,json=total,proto3" json:"Total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SearchResponse) GetHits() []*SearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *SearchResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// TrashedTask is a soft deleted task, it can be restored until it is purged
+type TrashedTask struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=DeletedAt,json=deletedAt,proto3" json:"DeletedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrashedTask) Reset() {
+	*x = TrashedTask{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrashedTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashedTask) ProtoMessage() {}
+
+func (x *TrashedTask) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashedTask.ProtoReflect.Descriptor instead.
+func (*TrashedTask) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TrashedTask) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *TrashedTask) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+type TrashList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*TrashedTask         `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrashList) Reset() {
+	*x = TrashList{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrashList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashList) ProtoMessage() {}
+
+func (x *TrashList) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashList.ProtoReflect.Descriptor instead.
+func (*TrashList) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TrashList) GetTasks() []*TrashedTask {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+// TaskEvent is one recorded change of a task. Before is empty for create
+type TaskEvent struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ID     string                 `protobuf:"bytes,1,opt,name=ID,json=id,proto3" json:"ID,omitempty"`
+	TaskID string                 `protobuf:"bytes,2,opt,name=TaskID,json=taskId,proto3" json:"TaskID,omitempty"`
+	// x-user-id of the request, "anonymous" when it was not sent
+	Actor string `protobuf:"bytes,3,opt,name=Actor,json=actor,proto3" json:"Actor,omitempty"`
+	// create, update, done, delete or restore
+	Operation     string                 `protobuf:"bytes,4,opt,name=Operation,json=operation,proto3" json:"Operation,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=At,json=at,proto3" json:"At,omitempty"`
+	Before        *Task                  `protobuf:"bytes,6,opt,name=Before,json=before,proto3" json:"Before,omitempty"`
+	After         *Task                  `protobuf:"bytes,7,opt,name=After,json=after,proto3" json:"After,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskEvent) Reset() {
+	*x = TaskEvent{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskEvent) ProtoMessage() {}
+
+func (x *TaskEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskEvent.ProtoReflect.Descriptor instead.
+func (*TaskEvent) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TaskEvent) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetTaskID() string {
+	if x != nil {
+		return x.TaskID
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *TaskEvent) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetBefore() *Task {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *TaskEvent) GetAfter() *Task {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+type TaskHistory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*TaskEvent           `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskHistory) Reset() {
+	*x = TaskHistory{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskHistory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskHistory) ProtoMessage() {}
+
+func (x *TaskHistory) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskHistory.ProtoReflect.Descriptor instead.
+func (*TaskHistory) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TaskHistory) GetEvents() []*TaskEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type Nothing struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dummy         bool                   `protobuf:"varint,1,opt,name=dummy,proto3" json:"dummy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Nothing) Reset() {
+	*x = Nothing{}
+	mi := &file_taskpb_v1_task_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Nothing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Nothing) ProtoMessage() {}
+
+func (x *Nothing) ProtoReflect() protoreflect.Message {
+	mi := &file_taskpb_v1_task_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Nothing.ProtoReflect.Descriptor instead.
+func (*Nothing) Descriptor() ([]byte, []int) {
+	return file_taskpb_v1_task_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Nothing) GetDummy() bool {
+	if x != nil {
+		return x.Dummy
+	}
+	return false
+}
+
+var File_taskpb_v1_task_proto protoreflect.FileDescriptor
+
+const file_taskpb_v1_task_proto_rawDesc = "" +
+	"\n" +
+	"\x14taskpb/v1/task.proto\x12\ttaskpb.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"L\n" +
+	"\n" +
+	"CreateTask\x12\x16\n" +
+	"\x06Header\x18\x01 \x01(\tR\x06header\x12\x12\n" +
+	"\x04Body\x18\x02 \x01(\tR\x04body\x12\x12\n" +
+	"\x04Tags\x18\x03 \x03(\tR\x04tags\"\x88\x01\n" +
+	"\x04Task\x12\x16\n" +
+	"\x06Header\x18\x01 \x01(\tR\x06header\x12\x12\n" +
+	"\x04Body\x18\x02 \x01(\tR\x04body\x12\x0e\n" +
+	"\x02ID\x18\x03 \x01(\tR\x02id\x12\x16\n" +
+	"\x06IsDone\x18\x04 \x01(\bR\x06isDone\x12\x18\n" +
+	"\aVersion\x18\x05 \x01(\x03R\aversion\x12\x12\n" +
+	"\x04Tags\x18\x06 \x03(\tR\x04tags\"\xd1\x01\n" +
+	"\n" +
+	"UpdateTask\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\x06Header\x18\x02 \x01(\tH\x00R\x06header\x88\x01\x01\x12\x17\n" +
+	"\x04Body\x18\x03 \x01(\tH\x01R\x04body\x88\x01\x01\x12\x1b\n" +
+	"\x06IsDone\x18\x04 \x01(\bH\x02R\x06isDone\x88\x01\x01\x12-\n" +
+	"\x0fExpectedVersion\x18\x05 \x01(\x03H\x03R\x0fexpectedVersion\x88\x01\x01B\t\n" +
+	"\a_HeaderB\a\n" +
+	"\x05_BodyB\t\n" +
+	"\a_IsDoneB\x12\n" +
+	"\x10_ExpectedVersion\"1\n" +
+	"\bTaskList\x12%\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x0f.taskpb.v1.TaskR\x05tasks\"[\n" +
+	"\x06TaskID\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02id\x12-\n" +
+	"\x0fExpectedVersion\x18\x02 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x12\n" +
+	"\x10_ExpectedVersion\"A\n" +
+	"\x12BatchCreateRequest\x12+\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x15.taskpb.v1.CreateTaskR\x05tasks\" \n" +
+	"\fBatchTaskIDs\x12\x10\n" +
+	"\x03IDs\x18\x01 \x03(\tR\x03ids\"]\n" +
+	"\x0fBatchItemResult\x12\x14\n" +
+	"\x05Index\x18\x01 \x01(\x05R\x05index\x12\x0e\n" +
+	"\x02ID\x18\x02 \x01(\tR\x02id\x12\x0e\n" +
+	"\x02Ok\x18\x03 \x01(\bR\x02ok\x12\x14\n" +
+	"\x05Error\x18\x04 \x01(\tR\x05error\"C\n" +
+	"\vBatchResult\x124\n" +
+	"\aresults\x18\x01 \x03(\v2\x1a.taskpb.v1.BatchItemResultR\aresults\"[\n" +
+	"\rSearchRequest\x12\x10\n" +
+	"\x05Query\x18\x01 \x01(\tR\x01q\x12\x1a\n" +
+	"\bPageSize\x18\x02 \x01(\x05R\bpageSize\x12\x1c\n" +
+	"\tPageToken\x18\x03 \x01(\tR\tpageToken\"\x8c\x01\n" +
+	"\tSearchHit\x12#\n" +
+	"\x04task\x18\x01 \x01(\v2\x0f.taskpb.v1.TaskR\x04task\x12\x12\n" +
+	"\x04Rank\x18\x02 \x01(\x02R\x04rank\x12$\n" +
+	"\rHeaderSnippet\x18\x03 \x01(\tR\rheaderSnippet\x12 \n" +
+	"\vBodySnippet\x18\x04 \x01(\tR\vbodySnippet\"v\n" +
+	"\x0eSearchResponse\x12(\n" +
+	"\x04hits\x18\x01 \x03(\v2\x14.taskpb.v1.SearchHitR\x04hits\x12$\n" +
+	"\rNextPageToken\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05Total\x18\x03 \x01(\x05R\x05total\"l\n" +
+	"\vTrashedTask\x12#\n" +
+	"\x04task\x18\x01 \x01(\v2\x0f.taskpb.v1.TaskR\x04task\x128\n" +
+	"\tDeletedAt\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\"9\n" +
+	"\tTrashList\x12,\n" +
+	"\x05tasks\x18\x01 \x03(\v2\x16.taskpb.v1.TrashedTaskR\x05tasks\"\xe3\x01\n" +
+	"\tTaskEvent\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06TaskID\x18\x02 \x01(\tR\x06taskId\x12\x14\n" +
+	"\x05Actor\x18\x03 \x01(\tR\x05actor\x12\x1c\n" +
+	"\tOperation\x18\x04 \x01(\tR\toperation\x12*\n" +
+	"\x02At\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12'\n" +
+	"\x06Before\x18\x06 \x01(\v2\x0f.taskpb.v1.TaskR\x06before\x12%\n" +
+	"\x05After\x18\a \x01(\v2\x0f.taskpb.v1.TaskR\x05after\";\n" +
+	"\vTaskHistory\x12,\n" +
+	"\x06events\x18\x01 \x03(\v2\x14.taskpb.v1.TaskEventR\x06events\"\x1f\n" +
+	"\aNothing\x12\x14\n" +
+	"\x05dummy\x18\x01 \x01(\bR\x05dummy2\xbb\t\n" +
+	"\vTaskService\x12V\n" +
+	"\x06Create\x12\x15.taskpb.v1.CreateTask\x1a\x12.taskpb.v1.Nothing\"!\x82\xd3\xe4\x93\x02\x1b:\x01*Z\v:\x01*\"\x06/tasks\"\t/v1/tasks\x12K\n" +
+	"\x04List\x12\x11.taskpb.v1.TaskID\x1a\x13.taskpb.v1.TaskList\"\x1b\x82\xd3\xe4\x93\x02\x15Z\b\x12\x06/tasks\x12\t/v1/tasks\x12V\n" +
+	"\x06Delete\x12\x11.taskpb.v1.TaskID\x1a\x12.taskpb.v1.Nothing\"%\x82\xd3\xe4\x93\x02\x1fZ\r*\v/tasks/{ID}*\x0e/v1/tasks/{ID}\x12^\n" +
+	"\x04Done\x12\x11.taskpb.v1.TaskID\x1a\x12.taskpb.v1.Nothing\"/\x82\xd3\xe4\x93\x02)Z\x12\"\x10/tasks/{ID}/done\"\x13/v1/tasks/{ID}/done\x12P\n" +
+	"\x03Get\x12\x11.taskpb.v1.TaskID\x1a\x0f.taskpb.v1.Task\"%\x82\xd3\xe4\x93\x02\x1fZ\r\x12\v/tasks/{ID}\x12\x0e/v1/tasks/{ID}\x12`\n" +
+	"\x06Update\x12\x15.taskpb.v1.UpdateTask\x1a\x12.taskpb.v1.Nothing\"+\x82\xd3\xe4\x93\x02%:\x01*Z\x10:\x01*2\v/tasks/{ID}2\x0e/v1/tasks/{ID}\x12f\n" +
+	"\vBatchCreate\x12\x1d.taskpb.v1.BatchCreateRequest\x1a\x16.taskpb.v1.BatchResult\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/tasks:batchCreate\x12\\\n" +
+	"\tBatchDone\x12\x17.taskpb.v1.BatchTaskIDs\x1a\x16.taskpb.v1.BatchResult\"\x1e\x82\xd3\xe4\x93\x02\x18:\x01*\"\x13/v1/tasks:batchDone\x12`\n" +
+	"\vBatchDelete\x12\x17.taskpb.v1.BatchTaskIDs\x1a\x16.taskpb.v1.BatchResult\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/tasks:batchDelete\x12h\n" +
+	"\x06Search\x12\x18.taskpb.v1.SearchRequest\x1a\x19.taskpb.v1.SearchResponse\")\x82\xd3\xe4\x93\x02#Z\x0f\x12\r/tasks/search\x12\x10/v1/tasks/search\x12P\n" +
+	"\aRestore\x12\x11.taskpb.v1.TaskID\x1a\x12.taskpb.v1.Nothing\"\x1e\x82\xd3\xe4\x93\x02\x18\"\x16/v1/tasks/{ID}/restore\x12G\n" +
+	"\tListTrash\x12\x11.taskpb.v1.TaskID\x1a\x14.taskpb.v1.TrashList\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/trash\x12n\n" +
+	"\n" +
+	"GetHistory\x12\x11.taskpb.v1.TaskID\x1a\x16.taskpb.v1.TaskHistory\"5\x82\xd3\xe4\x93\x02/Z\x15\x12\x13/tasks/{ID}/history\x12\x16/v1/tasks/{ID}/historyB\x1bZ\x19task-api/taskpb/v1;taskpbb\x06proto3"
+
+var (
+	file_taskpb_v1_task_proto_rawDescOnce sync.Once
+	file_taskpb_v1_task_proto_rawDescData []byte
+)
+
+func file_taskpb_v1_task_proto_rawDescGZIP() []byte {
+	file_taskpb_v1_task_proto_rawDescOnce.Do(func() {
+		file_taskpb_v1_task_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_taskpb_v1_task_proto_rawDesc), len(file_taskpb_v1_task_proto_rawDesc)))
+	})
+	return file_taskpb_v1_task_proto_rawDescData
+}
+
+var file_taskpb_v1_task_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_taskpb_v1_task_proto_goTypes = []any{
+	(*CreateTask)(nil),            // 0: taskpb.v1.CreateTask
+	(*Task)(nil),                  // 1: taskpb.v1.Task
+	(*UpdateTask)(nil),            // 2: taskpb.v1.UpdateTask
+	(*TaskList)(nil),              // 3: taskpb.v1.TaskList
+	(*TaskID)(nil),                // 4: taskpb.v1.TaskID
+	(*BatchCreateRequest)(nil),    // 5: taskpb.v1.BatchCreateRequest
+	(*BatchTaskIDs)(nil),          // 6: taskpb.v1.BatchTaskIDs
+	(*BatchItemResult)(nil),       // 7: taskpb.v1.BatchItemResult
+	(*BatchResult)(nil),           // 8: taskpb.v1.BatchResult
+	(*SearchRequest)(nil),         // 9: taskpb.v1.SearchRequest
+	(*SearchHit)(nil),             // 10: taskpb.v1.SearchHit
+	(*SearchResponse)(nil),        // 11: taskpb.v1.SearchResponse
+	(*TrashedTask)(nil),           // 12: taskpb.v1.TrashedTask
+	(*TrashList)(nil),             // 13: taskpb.v1.TrashList
+	(*TaskEvent)(nil),             // 14: taskpb.v1.TaskEvent
+	(*TaskHistory)(nil),           // 15: taskpb.v1.TaskHistory
+	(*Nothing)(nil),               // 16: taskpb.v1.Nothing
+	(*timestamppb.Timestamp)(nil), // 17: google.protobuf.Timestamp
+}
+var file_taskpb_v1_task_proto_depIdxs = []int32{
+	1,  // 0: taskpb.v1.TaskList.tasks:type_name -> taskpb.v1.Task
+	0,  // 1: taskpb.v1.BatchCreateRequest.tasks:type_name -> taskpb.v1.CreateTask
+	7,  // 2: taskpb.v1.BatchResult.results:type_name -> taskpb.v1.BatchItemResult
+	1,  // 3: taskpb.v1.SearchHit.task:type_name -> taskpb.v1.Task
+	10, // 4: taskpb.v1.SearchResponse.hits:type_name -> taskpb.v1.SearchHit
+	1,  // 5: taskpb.v1.TrashedTask.task:type_name -> taskpb.v1.Task
+	17, // 6: taskpb.v1.TrashedTask.DeletedAt:type_name -> google.protobuf.Timestamp
+	12, // 7: taskpb.v1.TrashList.tasks:type_name -> taskpb.v1.TrashedTask
+	17, // 8: taskpb.v1.TaskEvent.At:type_name -> google.protobuf.Timestamp
+	1,  // 9: taskpb.v1.TaskEvent.Before:type_name -> taskpb.v1.Task
+	1,  // 10: taskpb.v1.TaskEvent.After:type_name -> taskpb.v1.Task
+	14, // 11: taskpb.v1.TaskHistory.events:type_name -> taskpb.v1.TaskEvent
+	0,  // 12: taskpb.v1.TaskService.Create:input_type -> taskpb.v1.CreateTask
+	4,  // 13: taskpb.v1.TaskService.List:input_type -> taskpb.v1.TaskID
+	4,  // 14: taskpb.v1.TaskService.Delete:input_type -> taskpb.v1.TaskID
+	4,  // 15: taskpb.v1.TaskService.Done:input_type -> taskpb.v1.TaskID
+	4,  // 16: taskpb.v1.TaskService.Get:input_type -> taskpb.v1.TaskID
+	2,  // 17: taskpb.v1.TaskService.Update:input_type -> taskpb.v1.UpdateTask
+	5,  // 18: taskpb.v1.TaskService.BatchCreate:input_type -> taskpb.v1.BatchCreateRequest
+	6,  // 19: taskpb.v1.TaskService.BatchDone:input_type -> taskpb.v1.BatchTaskIDs
+	6,  // 20: taskpb.v1.TaskService.BatchDelete:input_type -> taskpb.v1.BatchTaskIDs
+	9,  // 21: taskpb.v1.TaskService.Search:input_type -> taskpb.v1.SearchRequest
+	4,  // 22: taskpb.v1.TaskService.Restore:input_type -> taskpb.v1.TaskID
+	4,  // 23: taskpb.v1.TaskService.ListTrash:input_type -> taskpb.v1.TaskID
+	4,  // 24: taskpb.v1.TaskService.GetHistory:input_type -> taskpb.v1.TaskID
+	16, // 25: taskpb.v1.TaskService.Create:output_type -> taskpb.v1.Nothing
+	3,  // 26: taskpb.v1.TaskService.List:output_type -> taskpb.v1.TaskList
+	16, // 27: taskpb.v1.TaskService.Delete:output_type -> taskpb.v1.Nothing
+	16, // 28: taskpb.v1.TaskService.Done:output_type -> taskpb.v1.Nothing
+	1,  // 29: taskpb.v1.TaskService.Get:output_type -> taskpb.v1.Task
+	16, // 30: taskpb.v1.TaskService.Update:output_type -> taskpb.v1.Nothing
+	8,  // 31: taskpb.v1.TaskService.BatchCreate:output_type -> taskpb.v1.BatchResult
+	8,  // 32: taskpb.v1.TaskService.BatchDone:output_type -> taskpb.v1.BatchResult
+	8,  // 33: taskpb.v1.TaskService.BatchDelete:output_type -> taskpb.v1.BatchResult
+	11, // 34: taskpb.v1.TaskService.Search:output_type -> taskpb.v1.SearchResponse
+	16, // 35: taskpb.v1.TaskService.Restore:output_type -> taskpb.v1.Nothing
+	13, // 36: taskpb.v1.TaskService.ListTrash:output_type -> taskpb.v1.TrashList
+	15, // 37: taskpb.v1.TaskService.GetHistory:output_type -> taskpb.v1.TaskHistory
+	25, // [25:38] is the sub-list for method output_type
+	12, // [12:25] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_taskpb_v1_task_proto_init() }
+func file_taskpb_v1_task_proto_init() {
+	if File_taskpb_v1_task_proto != nil {
+		return
+	}
+	file_taskpb_v1_task_proto_msgTypes[2].OneofWrappers = []any{}
+	file_taskpb_v1_task_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_taskpb_v1_task_proto_rawDesc), len(file_taskpb_v1_task_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_taskpb_v1_task_proto_goTypes,
+		DependencyIndexes: file_taskpb_v1_task_proto_depIdxs,
+		MessageInfos:      file_taskpb_v1_task_proto_msgTypes,
+	}.Build()
+	File_taskpb_v1_task_proto = out.File
+	file_taskpb_v1_task_proto_goTypes = nil
+	file_taskpb_v1_task_proto_depIdxs = nil
+}